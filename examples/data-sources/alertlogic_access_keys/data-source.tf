@@ -0,0 +1,7 @@
+data "alertlogic_access_keys" "stale" {
+  unused_for_days = 90
+}
+
+output "stale_access_keys" {
+  value = data.alertlogic_access_keys.stale.access_keys
+}
@@ -0,0 +1,7 @@
+data "alertlogic_user" "bob" {
+  email = "bob@bobloblawlaw.com"
+}
+
+output "bob_role_ids" {
+  value = data.alertlogic_user.bob.role_ids
+}
@@ -3,3 +3,13 @@ data "alertlogic_users" "all_users" {}
 output "users" {
   value = data.alertlogic_users.all_users.users
 }
+
+data "alertlogic_users" "active_without_mfa" {
+  active           = true
+  mfa_enabled      = false
+  include_role_ids = false
+}
+
+output "active_users_without_mfa" {
+  value = data.alertlogic_users.active_without_mfa.users[*].email
+}
@@ -0,0 +1,2 @@
+# Linked users can be imported using the user ID, the location and the ID of the linked user, separated by slashes.
+terraform import alertlogic_linked_user.bob_uk 715A4EC0-9833-4D6E-9C03-A537E3F98D23/defender-uk-newport/12345
@@ -0,0 +1,5 @@
+resource "alertlogic_linked_user" "bob_uk" {
+  user_id        = alertlogic_user.user.id
+  location       = "defender-uk-newport"
+  linked_user_id = 12345
+}
@@ -0,0 +1,6 @@
+# Users can be imported using their ID.
+terraform import alertlogic_user.user 715A4EC0-9833-4D6E-9C03-A537E3F98D23
+
+# Or looked up by their email address or username.
+terraform import alertlogic_user.user email:bob@bobloblawlaw.com
+terraform import alertlogic_user.user username:bob
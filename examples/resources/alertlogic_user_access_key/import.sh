@@ -0,0 +1,3 @@
+# User access keys can be imported using the user ID and access key ID, separated by a slash.
+# The secret key is not available after importing.
+terraform import alertlogic_user_access_key.automation 715A4EC0-9833-4D6E-9C03-A537E3F98D23/0123456789abcdef
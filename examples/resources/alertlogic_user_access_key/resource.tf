@@ -0,0 +1,23 @@
+resource "alertlogic_user" "automation" {
+  name               = "Automation"
+  email              = "automation@bobloblawlaw.com"
+  send_welcome_email = false
+}
+
+resource "alertlogic_user_access_key" "automation" {
+  user_id = alertlogic_user.automation.id
+  label   = "automation"
+  # An ASCII armored public key, e.g. from `gpg --export --armor`.
+  pgp_key = file("automation.pub")
+
+  # Replace the key every 90 days, creating the new key before the old one is deleted.
+  rotation_days = 90
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+output "encrypted_secret" {
+  value = alertlogic_user_access_key.automation.encrypted_secret
+}
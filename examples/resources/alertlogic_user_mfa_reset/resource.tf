@@ -0,0 +1,14 @@
+resource "alertlogic_user" "user" {
+  name        = "Bob Loblaw"
+  email       = "bob@bobloblawlaw.com"
+  require_mfa = true
+}
+
+# Change the ticket to clear Bob's MFA device again.
+resource "alertlogic_user_mfa_reset" "user" {
+  user_id = alertlogic_user.user.id
+
+  triggers = {
+    ticket = "HELP-1234"
+  }
+}
@@ -0,0 +1,2 @@
+# User role assignments can be imported using the user ID and role ID, separated by a slash.
+terraform import alertlogic_user_role_assignment.user_role 715A4EC0-9833-4D6E-9C03-A537E3F98D23/F578CCE5-9574-4489-BF05-A04075838DE3
@@ -0,0 +1,10 @@
+resource "alertlogic_user" "user" {
+  name         = "Bob Loblaw"
+  email        = "bob@bobloblawlaw.com"
+  ignore_roles = true
+}
+
+resource "alertlogic_user_role_assignment" "user_role" {
+  user_id = alertlogic_user.user.id
+  role_id = "F578CCE5-9574-4489-BF05-A04075838DE3"
+}
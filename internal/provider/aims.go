@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// aimsRequest sends a request to an endpoint the client library does not support, e.g.
+// `aims/v1/12345678/users/USER/access_keys`, and decodes the response into result unless it
+// is nil. Errors are formatted like those of the client library, so isNotFound works for both.
+func (c *client) aimsRequest(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", c.api.BaseURL, path), reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.api.APIToken != "" {
+		req.Header.Set("X-Aims-Auth-Token", c.api.APIToken)
+	}
+	if c.api.UserAgent != "" {
+		req.Header.Set("User-Agent", c.api.UserAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("HTTP status %d: content %q", resp.StatusCode, respBody)
+	}
+
+	if result == nil || len(respBody) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(respBody, result)
+}
@@ -0,0 +1,203 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/duffn/go-alertlogic/alertlogic"
+)
+
+const (
+	// defaultAPIURL is the Alert Logic API endpoint used when no other endpoint is configured.
+	defaultAPIURL = "https://api.cloudinsight.alertlogic.com"
+	// ukAPIURL is the Alert Logic API endpoint for accounts residing in the UK.
+	ukAPIURL = "https://api.cloudinsight.alertlogic.co.uk"
+	// totpPeriod is the validity period of a TOTP code.
+	totpPeriod = 30
+	// totpDigits is the number of digits in a TOTP code.
+	totpDigits = 6
+)
+
+// locations maps Alert Logic locations to the API endpoint serving them.
+var locations = map[string]string{
+	"defender-us-denver":  defaultAPIURL,
+	"defender-us-ashburn": defaultAPIURL,
+	"defender-uk-newport": ukAPIURL,
+}
+
+// locationNames returns the sorted names of all known locations.
+func locationNames() []string {
+	names := make([]string, 0, len(locations))
+	for name := range locations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// apiURL returns the base URL of the API. An explicitly configured URL always wins, otherwise
+// the URL is chosen by location, falling back to the default endpoint.
+func apiURL(location string, url string) (string, error) {
+	if url != "" {
+		return strings.TrimRight(url, "/"), nil
+	}
+
+	if location == "" {
+		return defaultAPIURL, nil
+	}
+
+	locationURL, ok := locations[location]
+	if !ok {
+		return "", fmt.Errorf("unknown location %q, expected one of %s", location, strings.Join(locationNames(), ", "))
+	}
+
+	return locationURL, nil
+}
+
+// authenticator obtains AIMS tokens with the configured credentials.
+type authenticator struct {
+	httpClient    *http.Client
+	baseURL       string
+	accountId     string
+	username      string
+	password      string
+	mfaCode       string
+	mfaTotpSecret string
+	// cache is optional and holds tokens across provider processes.
+	cache *tokenCache
+}
+
+// token returns a cached token if one is available, or authenticates otherwise.
+func (a *authenticator) token(ctx context.Context) (cachedToken, error) {
+	if a.cache != nil {
+		if token, ok := a.cache.get(a.baseURL, a.accountId, a.username); ok {
+			return token, nil
+		}
+	}
+
+	return a.authenticate(ctx)
+}
+
+// authenticate always requests a new token and stores it in the cache. A fresh MFA code is
+// generated if a TOTP secret is configured. A fixed MFA code is sent again, which AIMS may
+// reject once it has been used.
+func (a *authenticator) authenticate(ctx context.Context) (cachedToken, error) {
+	mfaCode := a.mfaCode
+	if a.mfaTotpSecret != "" {
+		code, err := totpCode(a.mfaTotpSecret, time.Now())
+		if err != nil {
+			return cachedToken{}, err
+		}
+		mfaCode = code
+	}
+
+	auth, err := authenticate(ctx, a.httpClient, a.baseURL, a.username, a.password, mfaCode)
+	if err != nil {
+		return cachedToken{}, err
+	}
+
+	token := cachedToken{
+		Token:           auth.Authentication.Token,
+		TokenExpiration: auth.Authentication.TokenExpiration,
+	}
+
+	if a.cache != nil {
+		if err := a.cache.put(a.baseURL, a.accountId, a.username, token); err != nil {
+			log.Printf("[WARN] Unable to cache the Alert Logic token: %s", err)
+		}
+	}
+
+	return token, nil
+}
+
+// authenticateRequest is the optional body sent with an authentication request.
+type authenticateRequest struct {
+	MfaCode string `json:"mfa_code,omitempty"`
+}
+
+// authenticate authenticates against AIMS with a username and password, or an access key and
+// secret key, and an optional MFA code. The client library neither supports MFA codes nor
+// other endpoints than the default one, so the request is made here and the resulting token
+// is handed to the client.
+func authenticate(ctx context.Context, httpClient *http.Client, baseURL string, username string, password string, mfaCode string) (alertlogic.AuthenticateResponse, error) {
+	var body []byte
+	if mfaCode != "" {
+		var err error
+		body, err = json.Marshal(authenticateRequest{MfaCode: mfaCode})
+		if err != nil {
+			return alertlogic.AuthenticateResponse{}, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/aims/v1/authenticate", baseURL), bytes.NewReader(body))
+	if err != nil {
+		return alertlogic.AuthenticateResponse{}, err
+	}
+	req.SetBasicAuth(username, password)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return alertlogic.AuthenticateResponse{}, fmt.Errorf("error authenticating: %s", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := ioutil.ReadAll(resp.Body)
+
+	switch {
+	case resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices:
+	case resp.StatusCode == http.StatusUnauthorized:
+		return alertlogic.AuthenticateResponse{}, fmt.Errorf("HTTP status %d: invalid credentials or MFA code", resp.StatusCode)
+	default:
+		return alertlogic.AuthenticateResponse{}, fmt.Errorf("HTTP status %d: content %q", resp.StatusCode, respBody)
+	}
+
+	var r alertlogic.AuthenticateResponse
+	if err := json.Unmarshal(respBody, &r); err != nil {
+		return alertlogic.AuthenticateResponse{}, fmt.Errorf("error unmarshalling the authentication response: %s", err)
+	}
+
+	return r, nil
+}
+
+// totpCode generates a time-based one-time password (RFC 6238) for a base32 encoded seed.
+func totpCode(secret string, t time.Time) (string, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return "", fmt.Errorf("invalid TOTP secret: %s", err)
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(t.Unix()/totpPeriod))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, value%mod), nil
+}
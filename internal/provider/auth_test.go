@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTotpCode(t *testing.T) {
+	// Test vectors from RFC 6238, truncated to six digits.
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	cases := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range cases {
+		code, err := totpCode(secret, time.Unix(unix, 0))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if code != expected {
+			t.Errorf("expected code %s at %d, got %s", expected, unix, code)
+		}
+	}
+}
+
+func TestTotpCode_invalidSecret(t *testing.T) {
+	if _, err := totpCode("not base32!", time.Now()); err == nil {
+		t.Fatal("expected an error for an invalid secret")
+	}
+}
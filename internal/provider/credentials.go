@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// defaultSharedCredentialsFile is the credentials file also used by the `alcli` tool.
+	defaultSharedCredentialsFile = "~/.alertlogic/config"
+	// defaultProfile is the profile read from the shared credentials file if none is configured.
+	defaultProfile = "default"
+)
+
+// sharedCredentials holds the settings of a single profile in a shared credentials file.
+type sharedCredentials struct {
+	AccountID   string
+	AccessKeyID string
+	SecretKey   string
+}
+
+// loadSharedCredentials reads a profile from an INI style shared credentials file, e.g.
+//
+//	[default]
+//	account_id = 12345678
+//	access_key_id = 0123456789abcdef
+//	secret_key = secret
+//
+// The returned bool reports whether the profile exists. An error satisfying os.IsNotExist is
+// returned if the file does not exist.
+func loadSharedCredentials(path string, profile string) (sharedCredentials, bool, error) {
+	path, err := expandHomeDir(path)
+	if err != nil {
+		return sharedCredentials{}, false, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return sharedCredentials{}, false, err
+	}
+	defer f.Close()
+
+	var creds sharedCredentials
+	found := false
+	section := ""
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section == profile {
+				found = true
+			}
+			continue
+		}
+
+		if section != profile {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(parts[1])
+
+		switch strings.TrimSpace(parts[0]) {
+		case "account_id":
+			creds.AccountID = value
+		case "access_key_id":
+			creds.AccessKeyID = value
+		case "secret_key":
+			creds.SecretKey = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return sharedCredentials{}, false, fmt.Errorf("error reading shared credentials file %s: %s", path, err)
+	}
+
+	return creds, found, nil
+}
+
+// readSharedCredentials reads a profile from the shared credentials file, falling back to the
+// default file and profile. A missing file or profile is only an error if it was configured.
+func readSharedCredentials(path string, profile string) (sharedCredentials, error) {
+	explicit := path != "" || profile != ""
+	if path == "" {
+		path = defaultSharedCredentialsFile
+	}
+	if profile == "" {
+		profile = defaultProfile
+	}
+
+	creds, found, err := loadSharedCredentials(path, profile)
+	if err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return sharedCredentials{}, nil
+		}
+		return sharedCredentials{}, fmt.Errorf("error reading shared credentials file: %s", err)
+	}
+
+	if !found {
+		if !explicit {
+			return sharedCredentials{}, nil
+		}
+		return sharedCredentials{}, fmt.Errorf("profile %q not found in shared credentials file %s", profile, path)
+	}
+
+	return creds, nil
+}
+
+// expandHomeDir replaces a leading `~` in a path with the user's home directory.
+func expandHomeDir(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testSharedCredentials = `# alcli configuration
+[default]
+account_id = 12345678
+access_key_id = defaultkey
+secret_key = defaultsecret
+
+[child]
+account_id=87654321
+access_key_id=childkey
+secret_key=child=secret
+`
+
+func writeTestSharedCredentials(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "alertlogic")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(path, []byte(testSharedCredentials), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return path
+}
+
+func TestLoadSharedCredentials(t *testing.T) {
+	path := writeTestSharedCredentials(t)
+
+	creds, found, err := loadSharedCredentials(path, "child")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !found {
+		t.Fatal("expected profile child to be found")
+	}
+
+	expected := sharedCredentials{AccountID: "87654321", AccessKeyID: "childkey", SecretKey: "child=secret"}
+	if creds != expected {
+		t.Errorf("expected %+v, got %+v", expected, creds)
+	}
+
+	if _, found, _ := loadSharedCredentials(path, "missing"); found {
+		t.Error("expected profile missing not to be found")
+	}
+
+	if _, _, err := loadSharedCredentials(filepath.Join(filepath.Dir(path), "missing"), "default"); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error, got %v", err)
+	}
+}
+
+func TestReadSharedCredentials(t *testing.T) {
+	path := writeTestSharedCredentials(t)
+
+	creds, err := readSharedCredentials(path, "")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if creds.AccessKeyID != "defaultkey" {
+		t.Errorf("expected the default profile, got %+v", creds)
+	}
+
+	if _, err := readSharedCredentials(path, "missing"); err == nil {
+		t.Error("expected an error for a configured profile that does not exist")
+	}
+
+	if _, err := readSharedCredentials(filepath.Join(filepath.Dir(path), "missing"), ""); err == nil {
+		t.Error("expected an error for a configured file that does not exist")
+	}
+}
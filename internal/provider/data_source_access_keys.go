@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/duffn/go-alertlogic/alertlogic"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceAccessKeys() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAccessKeysRead,
+		Description: `A list of the access keys of one or all Alert Logic users, e.g. to find stale keys.
+
+[API reference](https://console.cloudinsight.alertlogic.com/api/aims/#api-AIMS_User_Resources-ListUsers)
+		`,
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the access keys of this user. By default, the access keys of all users are returned.",
+			},
+			"older_than_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Only return access keys created more than this many days ago.",
+			},
+			"unused_for_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Only return access keys that have not been used to authenticate for this many days. Keys that have never been used count from their creation.",
+			},
+			"inactive_users_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return access keys of users that are inactive or locked, i.e. orphaned keys.",
+			},
+			"access_keys": {
+				Type:        schema.TypeList,
+				Description: "A list of access keys.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the user the access key belongs to.",
+						},
+						"user_email": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The email address of the user the access key belongs to.",
+						},
+						"user_active": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Indicates whether or not the user the access key belongs to is active.",
+						},
+						"user_locked": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Indicates whether or not the user the access key belongs to is locked.",
+						},
+						"access_key_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the access key.",
+						},
+						"label": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The label of the access key.",
+						},
+						"last_login": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "When the access key was last used to authenticate, as a Unix timestamp. Zero if it has never been used.",
+						},
+						"created": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Description: "Information on when the record was created.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAccessKeysRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	api := meta.(*client).api
+
+	var diags diag.Diagnostics
+
+	var users []alertlogic.User
+	if userId, ok := d.GetOk("user_id"); ok {
+		user, err := api.GetUserDetails(userId.(string), true, false, false)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		users = []alertlogic.User{user}
+	} else {
+		userList, err := api.ListUsers(true, false, false, "")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		users = userList.Users
+	}
+
+	olderThanDays := d.Get("older_than_days").(int)
+	unusedForDays := d.Get("unused_for_days").(int)
+	inactiveUsersOnly := d.Get("inactive_users_only").(bool)
+	now := time.Now()
+
+	accessKeyDetails := make([]interface{}, 0)
+	accessKeyIds := make([]string, 0)
+	for _, u := range users {
+		if u.AccessKeys == nil || (inactiveUsersOnly && u.Active && !u.Locked) {
+			continue
+		}
+
+		for _, k := range *u.AccessKeys {
+			created := time.Unix(int64(k.Created.At), 0)
+			if olderThanDays > 0 && created.After(now.AddDate(0, 0, -olderThanDays)) {
+				continue
+			}
+
+			lastUsed := created
+			if k.LastLogin > 0 {
+				lastUsed = time.Unix(int64(k.LastLogin), 0)
+			}
+			if unusedForDays > 0 && lastUsed.After(now.AddDate(0, 0, -unusedForDays)) {
+				continue
+			}
+
+			accessKeyDetails = append(accessKeyDetails, map[string]interface{}{
+				"user_id":       u.ID,
+				"user_email":    u.Email,
+				"user_active":   u.Active,
+				"user_locked":   u.Locked,
+				"access_key_id": k.AccessKeyID,
+				"label":         k.Label,
+				"last_login":    k.LastLogin,
+				"created":       map[string]interface{}{"at": fmt.Sprint(k.Created.At), "by": k.Created.By},
+			})
+			accessKeyIds = append(accessKeyIds, k.AccessKeyID)
+		}
+	}
+
+	if err := d.Set("access_keys", accessKeyDetails); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(stringListChecksum(accessKeyIds))
+
+	return diags
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDataSourceAccessKeysRead(t *testing.T) {
+	daysAgo := func(days int) int64 {
+		return time.Now().AddDate(0, 0, -days).Unix()
+	}
+
+	keys := fmt.Sprintf(`[
+		{"access_key_id":"NEW","created":{"at":%d},"last_login":%d},
+		{"access_key_id":"OLD_USED","created":{"at":%d},"last_login":%d},
+		{"access_key_id":"OLD_UNUSED","created":{"at":%d}}
+	]`, daysAgo(1), daysAgo(0), daysAgo(200), daysAgo(2), daysAgo(200))
+
+	meta := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("include_access_keys") != "true" {
+			t.Errorf("expected access keys to be included")
+		}
+		switch r.URL.Path {
+		case "/aims/v1/12345678/users":
+			w.Write([]byte(`{"users":[{"id":"BOB","email":"bob@bobloblawlaw.com","active":false,"access_keys":` + keys + `},{"id":"GOB","active":true,"access_keys":` + keys + `},{"id":"LUCILLE"}]}`))
+		case "/aims/v1/12345678/users/BOB":
+			w.Write([]byte(`{"id":"BOB","email":"bob@bobloblawlaw.com","active":false,"access_keys":` + keys + `}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	cases := []struct {
+		name     string
+		filters  map[string]interface{}
+		expected []string
+	}{
+		{"all users", map[string]interface{}{}, []string{"BOB/NEW", "BOB/OLD_USED", "BOB/OLD_UNUSED", "GOB/NEW", "GOB/OLD_USED", "GOB/OLD_UNUSED"}},
+		{"one user", map[string]interface{}{"user_id": "BOB"}, []string{"BOB/NEW", "BOB/OLD_USED", "BOB/OLD_UNUSED"}},
+		{"older than", map[string]interface{}{"older_than_days": 90}, []string{"BOB/OLD_USED", "BOB/OLD_UNUSED", "GOB/OLD_USED", "GOB/OLD_UNUSED"}},
+		{"unused for", map[string]interface{}{"unused_for_days": 30}, []string{"BOB/OLD_UNUSED", "GOB/OLD_UNUSED"}},
+		{"inactive users only", map[string]interface{}{"inactive_users_only": true}, []string{"BOB/NEW", "BOB/OLD_USED", "BOB/OLD_UNUSED"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := dataSourceAccessKeys()
+			d := r.TestResourceData()
+			for k, v := range c.filters {
+				d.Set(k, v)
+			}
+
+			if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			accessKeys := d.Get("access_keys").([]interface{})
+			ids := make([]string, 0, len(accessKeys))
+			for _, k := range accessKeys {
+				key := k.(map[string]interface{})
+				if key["user_id"] == "BOB" && (key["user_email"] != "bob@bobloblawlaw.com" || key["user_active"] != false) {
+					t.Errorf("unexpected owner details %v", key)
+				}
+				ids = append(ids, key["user_id"].(string)+"/"+key["access_key_id"].(string))
+			}
+			if strings.Join(ids, ",") != strings.Join(c.expected, ",") {
+				t.Errorf("expected access keys %v, got %v", c.expected, ids)
+			}
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/duffn/go-alertlogic/alertlogic"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -64,7 +63,7 @@ func dataSourceAccount() *schema.Resource {
 }
 
 func dataSourceAccountRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	api := meta.(*alertlogic.API)
+	api := meta.(*client).api
 
 	var diags diag.Diagnostics
 
@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 
-	"github.com/duffn/go-alertlogic/alertlogic"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -28,7 +27,7 @@ func dataSourceGlobalRoles() *schema.Resource {
 }
 
 func dataSourceGlobalRolesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	api := meta.(*alertlogic.API)
+	api := meta.(*client).api
 
 	var diags diag.Diagnostics
 
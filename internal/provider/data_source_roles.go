@@ -80,7 +80,7 @@ func dataSourceRoles() *schema.Resource {
 }
 
 func dataSourceRolesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	api := meta.(*alertlogic.API)
+	api := meta.(*client).api
 
 	var diags diag.Diagnostics
 
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/duffn/go-alertlogic/alertlogic"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceUser() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceUserRead,
+		Description: `A single Alert Logic user, looked up by ID, email address or username.
+
+[API reference](https://console.cloudinsight.alertlogic.com/api/aims/#api-AIMS_User_Resources-GetUserDetails)
+		`,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "email", "username"},
+				Description:  "The user's ID.",
+			},
+			"email": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "email", "username"},
+				Description:  "The user's email address.",
+			},
+			"username": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "email", "username"},
+				Description:  "The user's username.",
+			},
+			"account_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Account ID that holds the user.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The user's full name",
+			},
+			"active": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Indicates whether or not the user is active.",
+			},
+			"locked": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Indicates whether or not the user is allowed to log in.",
+			},
+			"mfa_enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Indicates the status of the users MFA.",
+			},
+			"version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The version of the user's details; i.e. how many times has the user been updated.",
+			},
+			"linked_users": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Users linked to this user.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user_id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The ID of the user.",
+						},
+						"location": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The location of the user.",
+						},
+					},
+				},
+			},
+			"role_ids": {
+				Description: "Role IDs for the user.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"created": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Information on when the record was created.",
+			},
+			"modified": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Information on when the record was modified.",
+			},
+		},
+	}
+}
+
+func dataSourceUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	api := meta.(*client).api
+
+	var diags diag.Diagnostics
+
+	var user alertlogic.User
+	var err error
+	lookup := "id"
+	if id, ok := d.GetOk("id"); ok {
+		user, err = api.GetUserDetails(id.(string), false, false, false)
+	} else {
+		if _, ok := d.GetOk("email"); ok {
+			lookup = "email"
+		} else {
+			lookup = "username"
+		}
+		user, err = lookupUser(api, lookup, d.Get(lookup).(string))
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	roleIds, err := api.GetAssignedRoleIDs(user.ID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for k, v := range flattenUser(user, roleIds.RoleIds) {
+		// Keep the configured value, so a difference in how Alert Logic stores it, e.g. in
+		// case, does not show up as a change.
+		if k == "id" || k == lookup {
+			continue
+		}
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(user.ID)
+
+	return diags
+}
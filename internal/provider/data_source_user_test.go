@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestDataSourceUserRead(t *testing.T) {
+	meta := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/aims/v1/12345678/users":
+			t.Errorf("unexpected request listing all users")
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/aims/v1/users/email/BOB@bobloblawlaw.com":
+			w.Write([]byte(`{"users":[
+				{"id":"OTHER","account_id":"87654321","name":"Bob Loblaw","email":"bob@bobloblawlaw.com","username":"bob"},
+				{"id":"BOB","account_id":"12345678","name":"Bob Loblaw","email":"bob@bobloblawlaw.com","username":"bob"}
+			]}`))
+		case r.URL.Path == "/aims/v1/user/username/bob":
+			w.Write([]byte(`{"id":"BOB","account_id":"12345678","name":"Bob Loblaw","email":"bob@bobloblawlaw.com","username":"bob"}`))
+		case r.URL.Path == "/aims/v1/user/username/gob":
+			w.Write([]byte(`{"id":"GOB","account_id":"87654321","name":"Gob Bluth","email":"gob@bluth.com","username":"gob"}`))
+		case r.URL.Path == "/aims/v1/12345678/users/BOB":
+			w.Write([]byte(`{"id":"BOB","account_id":"12345678","name":"Bob Loblaw","email":"bob@bobloblawlaw.com","username":"bob","active":true}`))
+		case r.URL.Path == "/aims/v1/12345678/users/BOB/role_ids":
+			w.Write([]byte(`{"role_ids":["ROLE"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	cases := map[string]string{
+		"id":       "BOB",
+		"email":    "BOB@bobloblawlaw.com",
+		"username": "bob",
+	}
+
+	for key, value := range cases {
+		t.Run(key, func(t *testing.T) {
+			r := dataSourceUser()
+			d := r.TestResourceData()
+			d.Set(key, value)
+
+			if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if d.Id() != "BOB" {
+				t.Errorf("expected ID BOB, got %s", d.Id())
+			}
+			if d.Get("name").(string) != "Bob Loblaw" {
+				t.Errorf("unexpected name %s", d.Get("name"))
+			}
+			roleIds := expandInterfaceToStringList(d.Get("role_ids"))
+			if len(roleIds) != 1 || roleIds[0] != "ROLE" {
+				t.Errorf("unexpected role IDs %v", roleIds)
+			}
+		})
+	}
+
+	// Users of other accounts and unknown users are not found.
+	for key, value := range map[string]string{"username": "gob", "email": "buster@bluth.com"} {
+		r := dataSourceUser()
+		d := r.TestResourceData()
+		d.Set(key, value)
+
+		if diags := r.ReadContext(context.Background(), d, meta); !diags.HasError() {
+			t.Errorf("expected an error looking up %s %s", key, value)
+		}
+	}
+}
@@ -3,12 +3,18 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sync"
 
 	"github.com/duffn/go-alertlogic/alertlogic"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// userRoleLookupConcurrency is the number of concurrent requests looking up role IDs of users.
+const userRoleLookupConcurrency = 8
+
 func dataSourceUsers() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceUsersRead,
@@ -17,6 +23,44 @@ func dataSourceUsers() *schema.Resource {
 [API reference](https://console.cloudinsight.alertlogic.com/api/aims/#api-AIMS_User_Resources-ListUsers)
 		`,
 		Schema: map[string]*schema.Schema{
+			"active": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return users that are, or are not, active.",
+			},
+			"locked": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return users that are, or are not, locked.",
+			},
+			"mfa_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return users that have, or do not have, MFA enabled.",
+			},
+			"email_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only return users whose email address matches this regular expression.",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only return users whose full name matches this regular expression.",
+			},
+			"role_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return users that have been granted this role.",
+			},
+			"include_role_ids": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether to look up the role IDs of the users. Disabling this saves requests when `role_ids` is not needed.",
+			},
 			"users": {
 				Type:        schema.TypeList,
 				Description: "A list of users.",
@@ -113,47 +157,34 @@ func dataSourceUsers() *schema.Resource {
 }
 
 func dataSourceUsersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	api := meta.(*alertlogic.API)
+	api := meta.(*client).api
 
 	var diags diag.Diagnostics
 
-	users, err := api.ListUsers(false, false, false, "")
+	includeRoleIds := d.Get("include_role_ids").(bool)
+
+	users, err := api.ListUsers(false, false, includeRoleIds, d.Get("role_id").(string))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	userDetails := make([]interface{}, 0)
-	userIds := make([]string, 0)
-	for _, v := range users.Users {
-		roleIds, err := api.GetAssignedRoleIDs(v.ID)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-
-		linkedUsersDetails := make([]interface{}, 0)
+	filtered, err := filterUsers(d, users.Users)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-		for _, u := range v.LinkedUsers {
-			linkedUsersDetails = append(linkedUsersDetails, map[string]interface{}{
-				"user_id":  u.UserID,
-				"location": u.Location,
-			})
+	roleIds := make([][]string, len(filtered))
+	if includeRoleIds {
+		roleIds, diags = getUsersRoleIds(ctx, api, filtered)
+		if diags.HasError() {
+			return diags
 		}
+	}
 
-		userDetails = append(userDetails, map[string]interface{}{
-			"id":           v.ID,
-			"account_id":   v.AccountID,
-			"name":         v.Name,
-			"username":     v.Username,
-			"email":        v.Email,
-			"active":       v.Active,
-			"locked":       v.Locked,
-			"mfa_enabled":  v.MfaEnabled,
-			"version":      v.Version,
-			"linked_users": linkedUsersDetails,
-			"role_ids":     roleIds.RoleIds,
-			"created":      map[string]interface{}{"at": fmt.Sprint(v.Created.At), "by": v.Created.By},
-			"modified":     map[string]interface{}{"at": fmt.Sprint(v.Modified.At), "by": v.Modified.By},
-		})
+	userDetails := make([]interface{}, 0)
+	userIds := make([]string, 0)
+	for i, v := range filtered {
+		userDetails = append(userDetails, flattenUser(v, roleIds[i]))
 		userIds = append(userIds, v.ID)
 	}
 
@@ -165,3 +196,139 @@ func dataSourceUsersRead(ctx context.Context, d *schema.ResourceData, meta inter
 
 	return diags
 }
+
+// filterUsers returns the users matching the filters of the `alertlogic_users` data source.
+func filterUsers(d *schema.ResourceData, users []alertlogic.User) ([]alertlogic.User, error) {
+	var emailRegex, nameRegex *regexp.Regexp
+	var err error
+	if v, ok := d.GetOk("email_regex"); ok {
+		if emailRegex, err = regexp.Compile(v.(string)); err != nil {
+			return nil, err
+		}
+	}
+	if v, ok := d.GetOk("name_regex"); ok {
+		if nameRegex, err = regexp.Compile(v.(string)); err != nil {
+			return nil, err
+		}
+	}
+
+	// GetOkExists is needed to tell a filter set to false apart from one that is not set.
+	active, filterActive := d.GetOkExists("active")
+	locked, filterLocked := d.GetOkExists("locked")
+	mfaEnabled, filterMfaEnabled := d.GetOkExists("mfa_enabled")
+
+	filtered := make([]alertlogic.User, 0, len(users))
+	for _, u := range users {
+		if filterActive && u.Active != active.(bool) {
+			continue
+		}
+		if filterLocked && u.Locked != locked.(bool) {
+			continue
+		}
+		if filterMfaEnabled && (u.MfaEnabled != nil && *u.MfaEnabled) != mfaEnabled.(bool) {
+			continue
+		}
+		if emailRegex != nil && !emailRegex.MatchString(u.Email) {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(u.Name) {
+			continue
+		}
+		filtered = append(filtered, u)
+	}
+
+	return filtered, nil
+}
+
+// getUsersRoleIds returns the role IDs of every user, in the order of users. Role IDs
+// included in the user list are used as is, the others are looked up concurrently by up to
+// userRoleLookupConcurrency workers. Lookups stop after the first failure and all failures
+// are returned as diagnostics.
+func getUsersRoleIds(ctx context.Context, api *alertlogic.API, users []alertlogic.User) ([][]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	roleIds := make([][]string, len(users))
+	pending := make(chan int, len(users))
+	for i, u := range users {
+		if u.RoleIds != nil {
+			roleIds[i] = *u.RoleIds
+			continue
+		}
+		pending <- i
+	}
+	close(pending)
+
+	var mu sync.Mutex
+	failed := false
+
+	var wg sync.WaitGroup
+	for w := 0; w < userRoleLookupConcurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range pending {
+				mu.Lock()
+				stop := failed || ctx.Err() != nil
+				mu.Unlock()
+				if stop {
+					continue
+				}
+
+				userRoleIds, err := api.GetAssignedRoleIDs(users[i].ID)
+
+				mu.Lock()
+				if err != nil {
+					failed = true
+					diags = append(diags, diag.Diagnostic{
+						Severity: diag.Error,
+						Summary:  fmt.Sprintf("Unable to get the role IDs of user %s", users[i].ID),
+						Detail:   err.Error(),
+					})
+				} else {
+					roleIds[i] = userRoleIds.RoleIds
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil && !diags.HasError() {
+		return nil, diag.FromErr(err)
+	}
+
+	return roleIds, diags
+}
+
+// flattenUser holds shared logic for the user details of `dataSourceUsersRead` and `dataSourceUserRead`.
+func flattenUser(user alertlogic.User, roleIds []string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           user.ID,
+		"account_id":   user.AccountID,
+		"name":         user.Name,
+		"username":     user.Username,
+		"email":        user.Email,
+		"active":       user.Active,
+		"locked":       user.Locked,
+		"mfa_enabled":  user.MfaEnabled,
+		"version":      user.Version,
+		"linked_users": flattenLinkedUsers(user.LinkedUsers),
+		"role_ids":     roleIds,
+		"created":      map[string]interface{}{"at": fmt.Sprint(user.Created.At), "by": user.Created.By},
+		"modified":     map[string]interface{}{"at": fmt.Sprint(user.Modified.At), "by": user.Modified.By},
+	}
+}
+
+// flattenLinkedUsers holds shared logic for the linked users of `dataSourceUsersRead` and `resourceUserRead`.
+func flattenLinkedUsers(linkedUsers []alertlogic.LinkedUser) []interface{} {
+	linkedUsersDetails := make([]interface{}, 0)
+
+	for _, u := range linkedUsers {
+		linkedUsersDetails = append(linkedUsersDetails, map[string]interface{}{
+			"user_id":  u.UserID,
+			"location": u.Location,
+		})
+	}
+
+	return linkedUsersDetails
+}
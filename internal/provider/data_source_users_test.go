@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newTestUsersHandler serves a list of count users. Even users include their role IDs, the role
+// IDs of odd users must be looked up. If failing, the list ends with a user whose role IDs
+// cannot be looked up.
+func newTestUsersHandler(count int, failing bool, lookups *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/aims/v1/12345678/users" {
+			users := make([]string, 0, count)
+			for i := 0; i < count; i++ {
+				if i%2 == 0 {
+					users = append(users, fmt.Sprintf(`{"id":"USER%d","role_ids":["ROLE%d"]}`, i, i))
+				} else {
+					users = append(users, fmt.Sprintf(`{"id":"USER%d"}`, i))
+				}
+			}
+			if failing {
+				users = append(users, `{"id":"failing"}`)
+			}
+			w.Write([]byte(`{"users":[` + strings.Join(users, ",") + `]}`))
+			return
+		}
+
+		var id string
+		if _, err := fmt.Sscanf(r.URL.Path, "/aims/v1/12345678/users/%s", &id); err == nil && strings.HasSuffix(id, "/role_ids") {
+			atomic.AddInt32(lookups, 1)
+			id = strings.TrimSuffix(id, "/role_ids")
+			if id == "failing" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte(fmt.Sprintf(`{"role_ids":["ROLE%s"]}`, strings.TrimPrefix(id, "USER"))))
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestDataSourceUsersRead(t *testing.T) {
+	var lookups int32
+	meta := newTestClient(t, newTestUsersHandler(50, false, &lookups))
+
+	r := dataSourceUsers()
+	d := r.TestResourceData()
+	d.Set("include_role_ids", true)
+
+	if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if lookups != 25 {
+		t.Errorf("expected 25 role lookups, got %d", lookups)
+	}
+
+	users := d.Get("users").([]interface{})
+	if len(users) != 50 {
+		t.Fatalf("expected 50 users, got %d", len(users))
+	}
+	for i, u := range users {
+		user := u.(map[string]interface{})
+		if user["id"] != fmt.Sprintf("USER%d", i) {
+			t.Errorf("expected USER%d at position %d, got %s", i, i, user["id"])
+		}
+		roleIds := expandInterfaceToStringList(user["role_ids"])
+		if len(roleIds) != 1 || roleIds[0] != fmt.Sprintf("ROLE%d", i) {
+			t.Errorf("unexpected role IDs of USER%d: %v", i, roleIds)
+		}
+	}
+}
+
+func TestDataSourceUsersRead_failedRoleLookup(t *testing.T) {
+	var lookups int32
+	meta := newTestClient(t, newTestUsersHandler(1, true, &lookups))
+
+	r := dataSourceUsers()
+	d := r.TestResourceData()
+	d.Set("include_role_ids", true)
+
+	diags := r.ReadContext(context.Background(), d, meta)
+	if !diags.HasError() {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(diags[0].Summary, "failing") {
+		t.Errorf("expected the failing user in the diagnostic, got %q", diags[0].Summary)
+	}
+}
+
+func TestDataSourceUsersRead_filters(t *testing.T) {
+	var query string
+	meta := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"users":[
+			{"id":"BOB","name":"Bob Loblaw","email":"bob@bobloblawlaw.com","active":true,"mfa_enabled":true},
+			{"id":"GOB","name":"Gob Bluth","email":"gob@bluth.com","active":true,"mfa_enabled":false},
+			{"id":"BUSTER","name":"Buster Bluth","email":"buster@bluth.com","active":true},
+			{"id":"LUCILLE","name":"Lucille Bluth","email":"lucille@bluth.com","active":false,"locked":true}
+		]}`))
+	})
+
+	cases := []struct {
+		name     string
+		filters  map[string]interface{}
+		expected []string
+	}{
+		{
+			name:     "none",
+			expected: []string{"BOB", "GOB", "BUSTER", "LUCILLE"},
+		},
+		{
+			name:     "active without MFA",
+			filters:  map[string]interface{}{"active": true, "mfa_enabled": false},
+			expected: []string{"GOB", "BUSTER"},
+		},
+		{
+			name:     "inactive",
+			filters:  map[string]interface{}{"active": false},
+			expected: []string{"LUCILLE"},
+		},
+		{
+			name:     "locked",
+			filters:  map[string]interface{}{"locked": true},
+			expected: []string{"LUCILLE"},
+		},
+		{
+			name:     "regexes",
+			filters:  map[string]interface{}{"email_regex": "@bluth\\.com$", "name_regex": "^(Gob|Lucille) "},
+			expected: []string{"GOB", "LUCILLE"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := dataSourceUsers()
+			d := r.TestResourceData()
+			d.Set("role_id", "ROLE")
+			for k, v := range c.filters {
+				d.Set(k, v)
+			}
+
+			if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if !strings.Contains(query, "role_id=ROLE") || !strings.Contains(query, "include_role_ids=false") {
+				t.Errorf("unexpected query %s", query)
+			}
+
+			users := d.Get("users").([]interface{})
+			ids := make([]string, 0, len(users))
+			for _, u := range users {
+				ids = append(ids, u.(map[string]interface{})["id"].(string))
+			}
+			if strings.Join(ids, ",") != strings.Join(c.expected, ",") {
+				t.Errorf("expected users %v, got %v", c.expected, ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+)
+
+const (
+	// maxLoggedBodyLength is the number of bytes of a request or response body that are logged.
+	maxLoggedBodyLength = 1024
+	// redacted replaces secrets in logged requests and responses.
+	redacted = "REDACTED"
+)
+
+// sensitiveHeaders are headers whose values are never logged.
+var sensitiveHeaders = []string{"Authorization", "X-Aims-Auth-Token"}
+
+// sensitiveFields are JSON fields whose values are never logged.
+var sensitiveFields = []string{"password", "secret_key", "token", "mfa_code"}
+
+// loggingTransport logs every API request and response at debug level, with secrets redacted.
+// Terraform shows these logs when TF_LOG is set to DEBUG or TRACE.
+type loggingTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !logging.IsDebugOrHigher() {
+		return t.transport.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err == nil {
+			reqBody, _ = ioutil.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	log.Printf("[DEBUG] Alert Logic API request: %s %s\nHeaders: %s\nBody: %s",
+		req.Method, req.URL.Path, redactHeaders(req.Header), redactBody(reqBody))
+
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(req)
+	latency := time.Since(start)
+
+	if err != nil {
+		log.Printf("[DEBUG] Alert Logic API request failed: %s %s (%s): %s", req.Method, req.URL.Path, latency, err)
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	log.Printf("[DEBUG] Alert Logic API response: %s %s: HTTP status %d (%s)\nBody: %s",
+		req.Method, req.URL.Path, resp.StatusCode, latency, redactBody(respBody))
+
+	return resp, nil
+}
+
+// redactHeaders formats headers for logging, hiding the values of sensitive headers.
+func redactHeaders(headers http.Header) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	formatted := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(headers[name], ", ")
+		for _, sensitive := range sensitiveHeaders {
+			if strings.EqualFold(name, sensitive) {
+				value = redacted
+			}
+		}
+		formatted = append(formatted, name+": "+value)
+	}
+
+	return strings.Join(formatted, "; ")
+}
+
+// redactBody formats a body for logging. Sensitive fields of JSON bodies are hidden and the
+// result is trimmed to maxLoggedBodyLength. Bodies that are not JSON are never logged, as
+// they cannot be redacted.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return "<empty>"
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "<non-JSON body omitted>"
+	}
+
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return "<non-JSON body omitted>"
+	}
+
+	if len(out) > maxLoggedBodyLength {
+		return string(out[:maxLoggedBodyLength]) + "...(trimmed)"
+	}
+
+	return string(out)
+}
+
+// redactValue walks a decoded JSON value and replaces the values of sensitive fields.
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSensitiveField(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+
+	return v
+}
+
+// isSensitiveField checks if a JSON field holds a secret.
+func isSensitiveField(key string) bool {
+	for _, field := range sensitiveFields {
+		if strings.EqualFold(key, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("X-Aims-Auth-Token", "token")
+	headers.Set("Authorization", "Basic a2V5OnNlY3JldA==")
+	headers.Set("Content-Type", "application/json")
+
+	formatted := redactHeaders(headers)
+	if strings.Contains(formatted, "token") || strings.Contains(formatted, "a2V5OnNlY3JldA==") {
+		t.Errorf("expected secrets to be redacted, got %s", formatted)
+	}
+	if !strings.Contains(formatted, "Content-Type: application/json") {
+		t.Errorf("expected other headers to be logged, got %s", formatted)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	body := `{"name":"Bob","password":"hunter2","authentication":{"token":"abc"},"access_keys":[{"access_key_id":"id","secret_key":"s3cret"}]}`
+
+	formatted := redactBody([]byte(body))
+	for _, secret := range []string{"hunter2", "abc", "s3cret"} {
+		if strings.Contains(formatted, secret) {
+			t.Errorf("expected %s to be redacted, got %s", secret, formatted)
+		}
+	}
+	for _, value := range []string{"Bob", `"access_key_id":"id"`} {
+		if !strings.Contains(formatted, value) {
+			t.Errorf("expected %s to be logged, got %s", value, formatted)
+		}
+	}
+
+	if formatted := redactBody([]byte("password=hunter2")); strings.Contains(formatted, "hunter2") {
+		t.Errorf("expected a non-JSON body to be omitted, got %s", formatted)
+	}
+
+	long := `{"name":"` + strings.Repeat("a", 2*maxLoggedBodyLength) + `"}`
+	if formatted := redactBody([]byte(long)); len(formatted) > maxLoggedBodyLength+len("...(trimmed)") {
+		t.Errorf("expected a long body to be trimmed, got %d bytes", len(formatted))
+	}
+}
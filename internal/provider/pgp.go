@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/openpgp"
+	// Keys without hash preferences require RIPEMD-160, which openpgp does not register itself.
+	_ "golang.org/x/crypto/ripemd160"
+)
+
+// encryptWithPGPKey encrypts a secret for a PGP public key, given either ASCII armored or
+// base64 encoded, in which case the key itself may be binary or armored. It returns the
+// fingerprint of the key and the base64 encoded encrypted secret, which can be decrypted with e.g.
+// `terraform output -raw encrypted_secret | base64 --decode | gpg --decrypt`.
+func encryptWithPGPKey(key string, secret string) (string, string, error) {
+	data := []byte(key)
+	if !isArmoredPGPKey(data) {
+		var err error
+		data, err = base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+		if err != nil {
+			return "", "", fmt.Errorf("error decoding PGP key: %s", err)
+		}
+	}
+
+	// Base64 encoded keys are usually binary, but may also be an armored file read with filebase64.
+	var entities openpgp.EntityList
+	var err error
+	if isArmoredPGPKey(data) {
+		entities, err = openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	} else {
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("error parsing PGP key: %s", err)
+	}
+	if len(entities) != 1 {
+		return "", "", fmt.Errorf("expected a single PGP key, got %d", len(entities))
+	}
+
+	var buf bytes.Buffer
+	w, err := openpgp.Encrypt(&buf, entities, nil, nil, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("error encrypting with PGP key: %s", err)
+	}
+	if _, err := w.Write([]byte(secret)); err != nil {
+		return "", "", fmt.Errorf("error encrypting with PGP key: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", "", fmt.Errorf("error encrypting with PGP key: %s", err)
+	}
+
+	fingerprint := hex.EncodeToString(entities[0].PrimaryKey.Fingerprint[:])
+
+	return fingerprint, base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// isArmoredPGPKey checks if data is an ASCII armored PGP public key.
+func isArmoredPGPKey(data []byte) bool {
+	return bytes.Contains(data, []byte("-----BEGIN PGP PUBLIC KEY BLOCK-----"))
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+func TestEncryptWithPGPKey(t *testing.T) {
+	entity, err := openpgp.NewEntity("Bob Loblaw", "", "bob@bobloblawlaw.com", nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var binary bytes.Buffer
+	if err := entity.Serialize(&binary); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var armored bytes.Buffer
+	w, err := armor.Encode(&armored, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	w.Close()
+
+	keys := map[string]string{
+		"base64":         base64.StdEncoding.EncodeToString(binary.Bytes()),
+		"armored":        armored.String(),
+		"base64 armored": base64.StdEncoding.EncodeToString(armored.Bytes()),
+	}
+
+	for name, key := range keys {
+		t.Run(name, func(t *testing.T) {
+			fingerprint, encrypted, err := encryptWithPGPKey(key, "secret")
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			if expected := hex.EncodeToString(entity.PrimaryKey.Fingerprint[:]); fingerprint != expected {
+				t.Errorf("expected fingerprint %s, got %s", expected, fingerprint)
+			}
+
+			data, err := base64.StdEncoding.DecodeString(encrypted)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			md, err := openpgp.ReadMessage(bytes.NewReader(data), openpgp.EntityList{entity}, nil, nil)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			decrypted, err := ioutil.ReadAll(md.UnverifiedBody)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if string(decrypted) != "secret" {
+				t.Errorf("expected the secret, got %q", decrypted)
+			}
+		})
+	}
+}
+
+func TestEncryptWithPGPKey_invalid(t *testing.T) {
+	for _, key := range []string{"not base64!", base64.StdEncoding.EncodeToString([]byte("not a key"))} {
+		if _, _, err := encryptWithPGPKey(key, "secret"); err == nil || !strings.Contains(err.Error(), "PGP key") {
+			t.Errorf("expected an error for %q, got %v", key, err)
+		}
+	}
+}
@@ -2,50 +2,181 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/duffn/go-alertlogic/alertlogic"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func init() {
 	schema.DescriptionKind = schema.StringMarkdown
 }
 
+// client is passed to all resources and data sources as their meta value.
+type client struct {
+	api *alertlogic.API
+	// httpClient sends requests to endpoints the client library does not support.
+	httpClient *http.Client
+	// readOnly prevents any resource from being created, updated or deleted.
+	readOnly bool
+}
+
 func New(version string) func() *schema.Provider {
 	return func() *schema.Provider {
 		p := &schema.Provider{
 			Schema: map[string]*schema.Schema{
 				"account_id": {
 					Type:        schema.TypeString,
-					Required:    true,
+					Optional:    true,
 					DefaultFunc: schema.EnvDefaultFunc("ALERTLOGIC_ACCOUNT_ID", nil),
-					Description: "Your Alert Logic Account ID.",
+					Description: "Your Alert Logic Account ID. Required unless it is set in the shared credentials file.",
+				},
+				"read_only": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("ALERTLOGIC_READ_ONLY", false),
+					Description: "Prevent any resource from being created, updated or deleted. Creates and updates fail while planning, deletes when applying. Data sources can still be read.",
+				},
+				"target_account_id": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("ALERTLOGIC_TARGET_ACCOUNT_ID", nil),
+					Description: "The ID of a managed (child) account to manage. The credentials of `account_id` are used to authenticate, but all resources and data sources operate on this account. It must be managed by `account_id`.",
 				},
 				"access_key_id": {
 					Type:        schema.TypeString,
-					Required:    true,
+					Optional:    true,
 					DefaultFunc: schema.EnvDefaultFunc("ALERTLOGIC_ACCESS_KEY_ID", nil),
-					Description: "Your Alert Logic API access key ID.",
+					Description: "Your Alert Logic API access key ID. Conflicts with `username` and `password`.",
 				},
 				"secret_key": {
 					Type:        schema.TypeString,
-					Required:    true,
+					Optional:    true,
 					Sensitive:   true,
 					DefaultFunc: schema.EnvDefaultFunc("ALERTLOGIC_SECRET_KEY", nil),
-					Description: "Your Alert Logic API secret key.",
+					Description: "Your Alert Logic API secret key. Conflicts with `username` and `password`.",
+				},
+				"username": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("ALERTLOGIC_USERNAME", nil),
+					Description: "Your Alert Logic username, usually your email address. Conflicts with `access_key_id` and `secret_key`.",
+				},
+				"password": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					DefaultFunc: schema.EnvDefaultFunc("ALERTLOGIC_PASSWORD", nil),
+					Description: "Your Alert Logic password. Conflicts with `access_key_id` and `secret_key`.",
+				},
+				"mfa_code": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					DefaultFunc: schema.EnvDefaultFunc("ALERTLOGIC_MFA_CODE", nil),
+					Description: "A one-time MFA code used with `username` and `password`. Conflicts with `mfa_totp_secret`.",
+				},
+				"mfa_totp_secret": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					DefaultFunc: schema.EnvDefaultFunc("ALERTLOGIC_MFA_TOTP_SECRET", nil),
+					Description: "The base32 encoded TOTP seed of your MFA device, used to generate an MFA code with `username` and `password`. Conflicts with `mfa_code`.",
+				},
+				"shared_credentials_file": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("ALERTLOGIC_SHARED_CREDENTIALS_FILE", nil),
+					Description: "The path to a shared credentials file, as used by `alcli`. Defaults to `" + defaultSharedCredentialsFile + "`. It is only read if no credentials are set in the provider configuration or environment.",
+				},
+				"profile": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("ALERTLOGIC_PROFILE", nil),
+					Description: "The profile to read from the shared credentials file. Defaults to `" + defaultProfile + "`.",
+				},
+				"token_cache": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("ALERTLOGIC_TOKEN_CACHE", false),
+					Description: "Cache authentication tokens on disk, so subsequent runs (e.g. `plan` and `apply`) reuse them until they expire instead of authenticating again. Tokens are stored per account and access key ID or username with permissions of `0600`.",
+				},
+				"token_cache_dir": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("ALERTLOGIC_TOKEN_CACHE_DIR", nil),
+					Description: "The directory cached tokens are stored in when `token_cache` is enabled. Defaults to `" + defaultTokenCacheDir + "`.",
+				},
+				"location": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("ALERTLOGIC_LOCATION", nil),
+					ValidateFunc: validation.StringInSlice(locationNames(), false),
+					Description:  "The Alert Logic location (data residency) of your account, e.g. `defender-us-denver` or `defender-uk-newport`. Selects the matching API endpoint. Defaults to the US endpoint.",
+				},
+				"api_url": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("ALERTLOGIC_API_URL", nil),
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+					Description:  "The base URL of the Alert Logic API. Overrides the endpoint selected by `location`.",
+				},
+				"max_retries": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      3,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "The maximum number of times a throttled (429) or failed (5xx) API request is retried. Only idempotent requests are retried.",
+				},
+				"min_backoff": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      1,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "The minimum number of seconds to wait before retrying an API request. The wait doubles with every retry. Must be at least 1.",
+				},
+				"max_backoff": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      30,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "The maximum number of seconds to wait before retrying an API request. Must be at least 1.",
+				},
+				"requests_per_second": {
+					Type:         schema.TypeFloat,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.FloatAtLeast(0),
+					Description:  "The maximum number of API requests per second, shared by all resources and data sources. `0` means no limit.",
+				},
+				"max_concurrent_requests": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "The maximum number of API requests in flight at the same time, shared by all resources and data sources. `0` means no limit.",
 				},
 			},
 			ResourcesMap: map[string]*schema.Resource{
 				"alertlogic_user":                     resourceUser(),
 				"alertlogic_assets_external_dns_name": resourceAssetsExternalDnsName(),
+				"alertlogic_user_role_assignment":     resourceUserRoleAssignment(),
+				"alertlogic_user_access_key":          resourceUserAccessKey(),
+				"alertlogic_user_mfa_reset":           resourceUserMfaReset(),
+				"alertlogic_linked_user":              resourceLinkedUser(),
 			},
 			DataSourcesMap: map[string]*schema.Resource{
+				"alertlogic_user":                      dataSourceUser(),
 				"alertlogic_users":                     dataSourceUsers(),
 				"alertlogic_roles":                     dataSourceRoles(),
 				"alertlogic_global_roles":              dataSourceGlobalRoles(),
 				"alertlogic_account":                   dataSourceAccount(),
 				"alertlogic_assets_external_dns_names": dataSourceAssetsExternalDNSNames(),
+				"alertlogic_access_keys":               dataSourceAccessKeys(),
 			},
 		}
 
@@ -57,26 +188,172 @@ func New(version string) func() *schema.Provider {
 
 func configure(version string, p *schema.Provider) func(context.Context, *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	return func(c context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-		var diags diag.Diagnostics
-
-		access_key_id := d.Get("access_key_id").(string)
-		secret_key := d.Get("secret_key").(string)
 		accountId := d.Get("account_id").(string)
+		accessKeyId := d.Get("access_key_id").(string)
+		secretKey := d.Get("secret_key").(string)
+		username := d.Get("username").(string)
+		password := d.Get("password").(string)
+		mfaCode := d.Get("mfa_code").(string)
+		mfaTotpSecret := d.Get("mfa_totp_secret").(string)
 
-		if access_key_id != "" && secret_key != "" && accountId != "" {
-			c, err := alertlogic.NewWithAccessKey(accountId, access_key_id, secret_key)
+		if accountId == "" || (accessKeyId == "" && secretKey == "" && username == "" && password == "") {
+			creds, err := readSharedCredentials(d.Get("shared_credentials_file").(string), d.Get("profile").(string))
 			if err != nil {
 				return nil, diag.FromErr(err)
 			}
 
-			return c, diags
-		} else {
-			diags = append(diags, diag.Diagnostic{
-				Severity: diag.Error,
-				Summary:  "Unable to create Alert Logic client",
-				Detail:   "You must set access_key_id, secret_key, and account_id",
-			})
+			if accountId == "" {
+				accountId = creds.AccountID
+			}
+			if accessKeyId == "" && secretKey == "" && username == "" && password == "" {
+				accessKeyId = creds.AccessKeyID
+				secretKey = creds.SecretKey
+			}
+		}
+
+		diags := validateCredentials(accountId, accessKeyId, secretKey, username, password, mfaCode, mfaTotpSecret)
+		if diags.HasError() {
 			return nil, diags
 		}
+
+		baseURL, err := apiURL(d.Get("location").(string), d.Get("api_url").(string))
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+
+		minBackoff := d.Get("min_backoff").(int)
+		maxBackoff := d.Get("max_backoff").(int)
+		if minBackoff > maxBackoff {
+			return nil, diag.Errorf("min_backoff (%d) must not be greater than max_backoff (%d)", minBackoff, maxBackoff)
+		}
+
+		// Requests are retried and rate limited individually, while re-authentication wraps
+		// them all, so a request that failed with an expired token is sent again as a whole.
+		transport := &retryTransport{
+			transport:  newRateLimitTransport(&loggingTransport{transport: http.DefaultTransport}, d.Get("requests_per_second").(float64), d.Get("max_concurrent_requests").(int)),
+			maxRetries: d.Get("max_retries").(int),
+			minBackoff: time.Duration(minBackoff) * time.Second,
+			maxBackoff: time.Duration(maxBackoff) * time.Second,
+		}
+
+		if accessKeyId != "" {
+			username, password = accessKeyId, secretKey
+		}
+
+		auth := &authenticator{
+			httpClient:    &http.Client{Transport: transport},
+			baseURL:       baseURL,
+			accountId:     accountId,
+			username:      username,
+			password:      password,
+			mfaCode:       mfaCode,
+			mfaTotpSecret: mfaTotpSecret,
+		}
+
+		if d.Get("token_cache").(bool) {
+			auth.cache, err = newTokenCache(d.Get("token_cache_dir").(string))
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+		}
+
+		token, err := auth.token(c)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+
+		httpClient := &http.Client{
+			Transport: &authTransport{
+				transport:     transport,
+				authenticator: auth,
+				token:         token,
+			},
+		}
+
+		api, err := newAPI(httpClient, baseURL, accountId, token.Token)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+
+		targetAccountId := d.Get("target_account_id").(string)
+		if targetAccountId != "" && targetAccountId != accountId {
+			statusCode, err := api.GetAccountRelationship(targetAccountId, alertlogic.Managed)
+			if statusCode == http.StatusNotFound {
+				return nil, diag.Diagnostics{{
+					Severity: diag.Error,
+					Summary:  "Invalid target account",
+					Detail:   fmt.Sprintf("Account %s is not managed by account %s.", targetAccountId, accountId),
+				}}
+			}
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+
+			api.AccountID = targetAccountId
+		}
+
+		return &client{api: api, httpClient: httpClient, readOnly: d.Get("read_only").(bool)}, diags
 	}
 }
+
+// validateCredentials checks that exactly one authentication mode has been configured: either
+// an access key and secret key, or a username and password with an optional MFA code.
+func validateCredentials(accountId, accessKeyId, secretKey, username, password, mfaCode, mfaTotpSecret string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if accountId == "" {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create Alert Logic client",
+			Detail:   "You must set account_id.",
+		})
+	}
+
+	accessKeyMode := accessKeyId != "" || secretKey != ""
+	passwordMode := username != "" || password != ""
+
+	switch {
+	case accessKeyMode && passwordMode:
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Conflicting Alert Logic credentials",
+			Detail:   "access_key_id and secret_key cannot be used together with username and password. Set only one pair.",
+		})
+	case accessKeyMode && (accessKeyId == "" || secretKey == ""):
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Incomplete Alert Logic credentials",
+			Detail:   "access_key_id and secret_key must be set together.",
+		})
+	case passwordMode && (username == "" || password == ""):
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Incomplete Alert Logic credentials",
+			Detail:   "username and password must be set together.",
+		})
+	case !accessKeyMode && !passwordMode:
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create Alert Logic client",
+			Detail:   "You must set either access_key_id and secret_key, or username and password.",
+		})
+	}
+
+	if mfaCode != "" && mfaTotpSecret != "" {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Conflicting Alert Logic MFA settings",
+			Detail:   "mfa_code and mfa_totp_secret cannot both be set.",
+		})
+	}
+
+	if accessKeyMode && (mfaCode != "" || mfaTotpSecret != "") {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Conflicting Alert Logic MFA settings",
+			Detail:   "mfa_code and mfa_totp_secret can only be used with username and password.",
+		})
+	}
+
+	return diags
+}
@@ -1,7 +1,14 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestProvider(t *testing.T) {
@@ -9,3 +16,188 @@ func TestProvider(t *testing.T) {
 		t.Fatalf("err: %s", err)
 	}
 }
+
+func TestValidateCredentials(t *testing.T) {
+	cases := []struct {
+		name                                                                          string
+		accountId, accessKeyId, secretKey, username, password, mfaCode, mfaTotpSecret string
+		expectError                                                                   bool
+	}{
+		{"access key", "1", "key", "secret", "", "", "", "", false},
+		{"username and password", "1", "", "", "user", "pass", "", "", false},
+		{"username, password and MFA code", "1", "", "", "user", "pass", "123456", "", false},
+		{"username, password and TOTP secret", "1", "", "", "user", "pass", "", "SEED", false},
+		{"no credentials", "1", "", "", "", "", "", "", true},
+		{"no account ID", "", "key", "secret", "", "", "", "", true},
+		{"both modes", "1", "key", "secret", "user", "pass", "", "", true},
+		{"missing secret key", "1", "key", "", "", "", "", "", true},
+		{"missing password", "1", "", "", "user", "", "", "", true},
+		{"MFA with access key", "1", "key", "secret", "", "", "123456", "", true},
+		{"MFA code and TOTP secret", "1", "", "", "user", "pass", "123456", "SEED", true},
+	}
+
+	for _, tc := range cases {
+		diags := validateCredentials(tc.accountId, tc.accessKeyId, tc.secretKey, tc.username, tc.password, tc.mfaCode, tc.mfaTotpSecret)
+		if diags.HasError() != tc.expectError {
+			t.Errorf("%s: expected error %t, got %v", tc.name, tc.expectError, diags)
+		}
+	}
+}
+
+func TestConfigure_apiURL(t *testing.T) {
+	accountAttempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/aims/v1/12345678/account" {
+			accountAttempts++
+			if accountAttempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "12345678"})
+			return
+		}
+
+		if r.URL.Path != "/aims/v1/authenticate" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		username, password, _ := r.BasicAuth()
+		if username != "key" || password != "secret" {
+			t.Errorf("unexpected credentials %s:%s", username, password)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"authentication": map[string]interface{}{"token": "token"},
+		})
+	}))
+	defer server.Close()
+
+	p := New("dev")()
+	d := schema.TestResourceDataRaw(t, p.Schema, map[string]interface{}{
+		"account_id":    "12345678",
+		"access_key_id": "key",
+		"secret_key":    "secret",
+		"location":      "defender-uk-newport",
+		"api_url":       server.URL + "/",
+		"min_backoff":   1,
+	})
+
+	meta, diags := p.ConfigureContextFunc(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	api := meta.(*client).api
+	if api.BaseURL != server.URL {
+		t.Errorf("expected base URL %s, got %s", server.URL, api.BaseURL)
+	}
+	if api.APIToken != "token" {
+		t.Errorf("expected token %q, got %q", "token", api.APIToken)
+	}
+
+	// Requests made by the client go through the provider's transport and are retried.
+	account, err := api.GetAccountDetails()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if account.ID != "12345678" {
+		t.Errorf("expected account 12345678, got %s", account.ID)
+	}
+	if accountAttempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", accountAttempts)
+	}
+}
+
+func TestApiURL(t *testing.T) {
+	cases := []struct {
+		location, url, expected string
+	}{
+		{"", "", defaultAPIURL},
+		{"defender-us-denver", "", defaultAPIURL},
+		{"defender-uk-newport", "", ukAPIURL},
+		{"defender-uk-newport", "http://localhost:8080/", "http://localhost:8080"},
+	}
+
+	for _, tc := range cases {
+		url, err := apiURL(tc.location, tc.url)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if url != tc.expected {
+			t.Errorf("expected %s for location %q and URL %q, got %s", tc.expected, tc.location, tc.url, url)
+		}
+	}
+
+	if _, err := apiURL("nowhere", ""); err == nil {
+		t.Error("expected an error for an unknown location")
+	}
+}
+
+func TestConfigure_targetAccountId(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/aims/v1/authenticate":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"authentication": map[string]interface{}{"token": "token"},
+			})
+		case "/aims/v1/12345678/accounts/managed/87654321":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := New("dev")()
+	configure := func(targetAccountId string) (interface{}, diag.Diagnostics) {
+		d := schema.TestResourceDataRaw(t, p.Schema, map[string]interface{}{
+			"account_id":        "12345678",
+			"target_account_id": targetAccountId,
+			"access_key_id":     "key",
+			"secret_key":        "secret",
+			"api_url":           server.URL,
+		})
+		return p.ConfigureContextFunc(context.Background(), d)
+	}
+
+	meta, diags := configure("87654321")
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if accountId := meta.(*client).api.AccountID; accountId != "87654321" {
+		t.Errorf("expected requests to be scoped to account 87654321, got %s", accountId)
+	}
+
+	if _, diags := configure("11111111"); !diags.HasError() {
+		t.Error("expected an error for an account that is not managed")
+	}
+}
+
+// newTestClient creates a client for account 12345678 that sends all requests to handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	api, err := newAPI(server.Client(), server.URL, "12345678", "token")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return &client{api: api, httpClient: server.Client()}
+}
+
+func TestProvider_backoff(t *testing.T) {
+	// A zero minimum backoff would never grow, so retries would be sent back-to-back, and a
+	// zero maximum backoff is always less than the minimum.
+	for _, key := range []string{"min_backoff", "max_backoff"} {
+		validate := New("dev")().Schema[key].ValidateFunc
+		if _, errs := validate(0, key); len(errs) == 0 {
+			t.Errorf("expected a %s of 0 to be rejected", key)
+		}
+		if _, errs := validate(1, key); len(errs) != 0 {
+			t.Errorf("unexpected errors: %v", errs)
+		}
+	}
+}
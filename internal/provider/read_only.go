@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// customizeDiffReadOnly fails the plan of a resource that would be created or updated while
+// the provider is configured with `read_only`.
+func customizeDiffReadOnly(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	c, ok := meta.(*client)
+	if !ok || !c.readOnly {
+		return nil
+	}
+
+	if d.Id() == "" {
+		return fmt.Errorf("the provider is configured with read_only = true, this resource cannot be created")
+	}
+	if len(d.GetChangedKeysPrefix("")) > 0 {
+		return fmt.Errorf("the provider is configured with read_only = true, resource %s cannot be updated", d.Id())
+	}
+
+	return nil
+}
+
+// readOnlyDelete wraps a delete function so it fails while the provider is configured with
+// `read_only`. Terraform does not customize the diff of a resource that is destroyed, so
+// deletes can only be stopped when applying.
+func readOnlyDelete(f schema.DeleteContextFunc) schema.DeleteContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		if meta.(*client).readOnly {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  "Provider is read-only",
+				Detail:   fmt.Sprintf("The provider is configured with read_only = true, resource %s cannot be deleted.", d.Id()),
+			}}
+		}
+
+		return f(ctx, d, meta)
+	}
+}
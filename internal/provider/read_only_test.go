@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestReadOnly_allResources(t *testing.T) {
+	for name, r := range New("dev")().ResourcesMap {
+		if r.CustomizeDiff == nil {
+			t.Errorf("%s: expected a CustomizeDiff rejecting changes in read-only mode", name)
+		}
+
+		d := r.TestResourceData()
+		d.SetId("id")
+		if diags := r.DeleteContext(context.Background(), d, &client{readOnly: true}); !diags.HasError() {
+			t.Errorf("%s: expected delete to fail in read-only mode", name)
+		}
+	}
+}
+
+func TestReadOnly_create(t *testing.T) {
+	r := resourceUser()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":  "Bob Loblaw",
+		"email": "bob@bobloblawlaw.com",
+	})
+
+	if _, err := r.Diff(context.Background(), nil, config, &client{readOnly: true}); err == nil {
+		t.Error("expected the plan to fail in read-only mode")
+	}
+
+	if _, err := r.Diff(context.Background(), nil, config, &client{}); err != nil {
+		t.Errorf("expected the plan to succeed, got %s", err)
+	}
+}
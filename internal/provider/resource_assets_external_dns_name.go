@@ -20,7 +20,8 @@ This is only the asset of the ` + "`external-dns-name`" + ` type. You cannot cre
 [API reference](https://console.cloudinsight.alertlogic.com/api/assets_write/#api-DeclareModify-DeclareAsset)`,
 		CreateContext: resourceAssetsExternalDnsNameCreate,
 		ReadContext:   resourceAssetsExternalDnsNameRead,
-		DeleteContext: resourceAssetsExternalDnsNameDelete,
+		DeleteContext: readOnlyDelete(resourceAssetsExternalDnsNameDelete),
+		CustomizeDiff: customizeDiffReadOnly,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				deploymentId, dnsName, err := parseAssetImportId(d.Id())
@@ -56,7 +57,7 @@ This is only the asset of the ` + "`external-dns-name`" + ` type. You cannot cre
 }
 
 func resourceAssetsExternalDnsNameCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	api := meta.(*alertlogic.API)
+	api := meta.(*client).api
 
 	deploymentId := d.Get("deployment_id").(string)
 	dnsName := d.Get("dns_name").(string)
@@ -92,7 +93,7 @@ func resourceAssetsExternalDnsNameCreate(ctx context.Context, d *schema.Resource
 }
 
 func resourceAssetsExternalDnsNameRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	api := meta.(*alertlogic.API)
+	api := meta.(*client).api
 
 	var diags diag.Diagnostics
 
@@ -104,6 +105,9 @@ func resourceAssetsExternalDnsNameRead(ctx context.Context, d *schema.ResourceDa
 	}
 
 	thisAsset := getAssetFromList(assetId, assets)
+	if thisAsset == (alertlogic.ExternalDNSNameAsset{}) {
+		return removeFromState(d, "External DNS name asset")
+	}
 
 	if err := d.Set("deployment_id", thisAsset.DeploymentID); err != nil {
 		return diag.FromErr(err)
@@ -116,7 +120,7 @@ func resourceAssetsExternalDnsNameRead(ctx context.Context, d *schema.ResourceDa
 }
 
 func resourceAssetsExternalDnsNameDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	api := meta.(*alertlogic.API)
+	api := meta.(*client).api
 
 	var diags diag.Diagnostics
 
@@ -124,7 +128,7 @@ func resourceAssetsExternalDnsNameDelete(ctx context.Context, d *schema.Resource
 	dnsName := d.Get("dns_name").(string)
 
 	_, err := api.RemoveExternalDNSNameAsset(deploymentId, dnsName)
-	if err != nil {
+	if err != nil && !isNotFound(err) {
 		return diag.FromErr(err)
 	}
 
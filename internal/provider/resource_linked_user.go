@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceLinkedUser() *schema.Resource {
+	return &schema.Resource{
+		Description: `A link between an Alert Logic user in this account and a user in another location, e.g. to give staff in the US and the UK one login path.
+
+The links of a user are also exposed as ` + "`linked_users`" + ` of ` + "`alertlogic_user`" + ` and the ` + "`alertlogic_users`" + ` data source.
+
+[API reference](https://console.cloudinsight.alertlogic.com/api/aims/#api-AIMS_User_Resources)`,
+		CreateContext: resourceLinkedUserCreate,
+		ReadContext:   resourceLinkedUserRead,
+		DeleteContext: readOnlyDelete(resourceLinkedUserDelete),
+		CustomizeDiff: customizeDiffReadOnly,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				userId, location, linkedUserId, err := parseLinkedUserId(d.Id())
+				if err != nil {
+					return nil, err
+				}
+
+				d.Set("user_id", userId)
+				d.Set("location", location)
+				d.Set("linked_user_id", linkedUserId)
+
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Description: "The ID of the user in this account.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"location": {
+				Description:  "The location of the linked user. One of `" + strings.Join(locationNames(), "`, `") + "`.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(locationNames(), false),
+			},
+			"linked_user_id": {
+				Description: "The ID of the user in the other location.",
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+			},
+		},
+	}
+}
+
+func resourceLinkedUserCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*client)
+
+	userId := d.Get("user_id").(string)
+	location := d.Get("location").(string)
+	linkedUserId := int64(d.Get("linked_user_id").(int))
+
+	err := c.aimsRequest(ctx, http.MethodPut, linkedUserPath(c, userId, location, linkedUserId), nil, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(getLinkedUserId(userId, location, linkedUserId))
+	return resourceLinkedUserRead(ctx, d, meta)
+}
+
+func resourceLinkedUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	api := meta.(*client).api
+
+	var diags diag.Diagnostics
+
+	userId, location, linkedUserId, err := parseLinkedUserId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	user, err := api.GetUserDetails(userId, false, false, false)
+	if isNotFound(err) {
+		return removeFromState(d, "Linked user")
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	found := false
+	for _, u := range user.LinkedUsers {
+		if u.UserID == linkedUserId && u.Location == location {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return removeFromState(d, "Linked user")
+	}
+
+	if err := d.Set("user_id", userId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("location", location); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("linked_user_id", linkedUserId); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+func resourceLinkedUserDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*client)
+
+	var diags diag.Diagnostics
+
+	userId, location, linkedUserId, err := parseLinkedUserId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.aimsRequest(ctx, http.MethodDelete, linkedUserPath(c, userId, location, linkedUserId), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// linkedUserPath returns the AIMS path of a link between users.
+func linkedUserPath(c *client, userId string, location string, linkedUserId int64) string {
+	return fmt.Sprintf("aims/v1/%s/users/%s/linked_users/%s/%d", c.api.AccountID, userId, location, linkedUserId)
+}
+
+// getLinkedUserId returns the ID of a linked user.
+func getLinkedUserId(userId string, location string, linkedUserId int64) string {
+	return fmt.Sprintf("%s/%s/%d", userId, location, linkedUserId)
+}
+
+// parseLinkedUserId parses the ID of a linked user. The ID should be in the format
+// `userId/location/linkedUserId`.
+func parseLinkedUserId(id string) (string, string, int64, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", 0, fmt.Errorf("unexpected format of ID (%s), expected userId/location/linkedUserId", id)
+	}
+
+	linkedUserId, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("unexpected format of ID (%s), linkedUserId must be a number", id)
+	}
+
+	return parts[0], parts[1], linkedUserId, nil
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestResourceLinkedUser(t *testing.T) {
+	linked := false
+	meta := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path := "/aims/v1/12345678/users/USER/linked_users/defender-uk-newport/42"
+		switch {
+		case r.Method == "PUT" && r.URL.Path == path:
+			linked = true
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "DELETE" && r.URL.Path == path:
+			linked = false
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "GET" && r.URL.Path == "/aims/v1/12345678/users/USER":
+			if linked {
+				w.Write([]byte(`{"id":"USER","linked_users":[{"user_id":42,"location":"defender-uk-newport"}]}`))
+				return
+			}
+			w.Write([]byte(`{"id":"USER","linked_users":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	r := resourceLinkedUser()
+	d := r.TestResourceData()
+	d.Set("user_id", "USER")
+	d.Set("location", "defender-uk-newport")
+	d.Set("linked_user_id", 42)
+
+	if diags := r.CreateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "USER/defender-uk-newport/42" {
+		t.Errorf("unexpected ID %s", d.Id())
+	}
+
+	if diags := r.DeleteContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if linked {
+		t.Error("expected the link to be deleted")
+	}
+
+	d.SetId("USER/defender-uk-newport/42")
+	if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Error("expected the deleted link to be removed from the state")
+	}
+}
+
+func TestParseLinkedUserId(t *testing.T) {
+	userId, location, linkedUserId, err := parseLinkedUserId("USER/defender-uk-newport/42")
+	if err != nil || userId != "USER" || location != "defender-uk-newport" || linkedUserId != 42 {
+		t.Errorf("unexpected result %s, %s, %d, %v", userId, location, linkedUserId, err)
+	}
+
+	for _, id := range []string{"USER", "USER/defender-uk-newport", "USER/defender-uk-newport/bob", "/defender-uk-newport/42", "USER/defender-uk-newport/42/1"} {
+		if _, _, _, err := parseLinkedUserId(id); err == nil {
+			t.Errorf("expected an error for %s", id)
+		}
+	}
+}
@@ -2,10 +2,15 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/duffn/go-alertlogic/alertlogic"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceUser() *schema.Resource {
@@ -16,9 +21,13 @@ func resourceUser() *schema.Resource {
 		CreateContext: resourceUserCreate,
 		ReadContext:   resourceUserRead,
 		UpdateContext: resourceUserUpdate,
-		DeleteContext: resourceUserDelete,
+		DeleteContext: readOnlyDelete(resourceUserDelete),
+		CustomizeDiff: customdiff.All(
+			customizeDiffReadOnly,
+			resourceUserCustomizeDiff,
+		),
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceUserImport,
 		},
 		Schema: map[string]*schema.Schema{
 			"id": {
@@ -48,19 +57,117 @@ func resourceUser() *schema.Resource {
 				Optional:    true,
 			},
 			"role_ids": {
-				Description: "An array of role IDs to grant to the user.",
+				Description: "An array of role IDs to grant to the user. Roles not in this list are revoked from the user.",
 				Type:        schema.TypeList,
 				Optional:    true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
+			"ignore_roles": {
+				Description: "Do not manage the user's roles with this resource, e.g. when they are granted with `alertlogic_user_role_assignment` or outside of Terraform. `role_ids` must not be set.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"send_welcome_email": {
+				Description: "Whether Alert Logic emails the user a link to set their password when the user is created, including when it is recreated. When `false`, the user is created with `password`, or a random password if none is set. Only used on creation.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"password": {
+				Description: "The user's password. Requires `send_welcome_email` to be `false` when the user is created. The password is only sent when it changes and is never read back from Alert Logic.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"one_time_password": {
+				Description: "Whether `password` is a one-time password that the user must change on their first login.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"welcome_email_trigger": {
+				Description: "An arbitrary value that, when changed, sends the welcome email again. Cannot be changed together with `password`.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"username": {
+				Description: "The user's username.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"locked": {
+				Description: "Indicates whether or not the user is locked out, e.g. after too many failed logins. Set to `false` to unlock the user. Users cannot be locked, set `active` to `false` instead.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+			},
+			"delete_behavior": {
+				Description:  "What happens to the user in Alert Logic when the resource is destroyed. `delete` deletes the user, `deactivate` only sets the user inactive to keep its audit history.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "delete",
+				ValidateFunc: validation.StringInSlice([]string{"delete", "deactivate"}, false),
+			},
+			"mfa_enabled": {
+				Description: "Indicates the status of the user's MFA.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"require_mfa": {
+				Description: "Whether the user must log in with multi-factor authentication. If not set, the current setting of the user is left alone.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+			},
+			"version": {
+				Description: "The version of the user's details; i.e. how many times has the user been updated.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"linked_users": {
+				Description: "Users in other locations linked to this user.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user_id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The ID of the user.",
+						},
+						"location": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The location of the user.",
+						},
+					},
+				},
+			},
+			"created": {
+				Description: "Information on when the record was created.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+			},
+			"modified": {
+				Description: "Information on when the record was modified.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+			},
 		},
 	}
 }
 
 func resourceUserCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	api := meta.(*alertlogic.API)
+	api := meta.(*client).api
+
+	// The password may not have been known when the plan was checked.
+	err := validateUserPassword(d.Get("send_welcome_email").(bool), d.Get("one_time_password").(bool), d.Get("password").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	createUser := alertlogic.CreateUserRequest{
 		Name:        d.Get("name").(string),
@@ -69,36 +176,91 @@ func resourceUserCreate(ctx context.Context, d *schema.ResourceData, meta interf
 		MobilePhone: d.Get("mobile_phone").(string),
 	}
 
-	user, err := api.CreateUser(createUser, false)
+	// Alert Logic only emails a link to set the password to users created without one.
+	oneTimePassword := false
+	if !d.Get("send_welcome_email").(bool) {
+		createUser.Password = d.Get("password").(string)
+		oneTimePassword = d.Get("one_time_password").(bool)
+
+		if createUser.Password == "" {
+			password, err := randomPassword()
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			createUser.Password = password
+		}
+	}
+
+	user, err := api.CreateUser(createUser, oneTimePassword)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	roleIds := expandInterfaceToStringList(d.Get("role_ids"))
-	for _, roleId := range roleIds {
-		_, err := api.GrantUserRole(user.ID, roleId)
-		if err != nil {
-			return diag.FromErr(err)
+	// Track the user right away, so it is never orphaned if granting its roles fails.
+	d.SetId(user.ID)
+
+	grantedRoleIds := make([]string, 0)
+	if !d.Get("ignore_roles").(bool) {
+		for _, roleId := range expandInterfaceToStringList(d.Get("role_ids")) {
+			_, err := api.GrantUserRole(user.ID, roleId)
+			if err != nil {
+				return resourceUserRollback(d, api, grantedRoleIds, fmt.Errorf("error granting role %s to user %s: %s", roleId, user.ID, err))
+			}
+			grantedRoleIds = append(grantedRoleIds, roleId)
+		}
+	}
+
+	// New users are active and not required to use MFA, so only the opposite needs a request.
+	fields := map[string]interface{}{}
+	if !d.Get("active").(bool) {
+		fields["active"] = false
+	}
+	if requireMfa, ok := d.GetOkExists("require_mfa"); ok && requireMfa.(bool) {
+		fields["mfa_enabled"] = true
+	}
+	if len(fields) > 0 {
+		if err := updateUserFields(ctx, meta.(*client), user.ID, fields); err != nil {
+			return resourceUserRollback(d, api, grantedRoleIds, err)
 		}
 	}
 
-	d.SetId(user.ID)
 	return resourceUserRead(ctx, d, meta)
 }
 
+// resourceUserRollback deletes a user whose creation failed halfway, so the next apply
+// creates it from scratch. If the user cannot be deleted, the roles granted so far are
+// kept in the state and Terraform marks the user as tainted, so it is replaced.
+func resourceUserRollback(d *schema.ResourceData, api *alertlogic.API, grantedRoleIds []string, createErr error) diag.Diagnostics {
+	diags := diag.FromErr(createErr)
+
+	_, err := api.DeleteUser(d.Id())
+	if err == nil {
+		d.SetId("")
+		return diags
+	}
+
+	if err := d.Set("role_ids", grantedRoleIds); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	return append(diags, diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  "Unable to roll back user creation",
+		Detail:   fmt.Sprintf("User %s was created, but could not be deleted after its roles failed to be granted: %s. It has been saved to the state and will be replaced on the next apply.", d.Id(), err),
+	})
+}
+
 func resourceUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	api := meta.(*alertlogic.API)
+	api := meta.(*client).api
 
 	var diags diag.Diagnostics
 
 	userId := d.Id()
 
 	user, err := api.GetUserDetailsById(userId, false, false, false)
-	if err != nil {
-		return diag.FromErr(err)
+	if isNotFound(err) {
+		return removeFromState(d, "User")
 	}
-
-	roleIds, err := api.GetAssignedRoleIDs(user.ID)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -115,6 +277,52 @@ func resourceUserRead(ctx context.Context, d *schema.ResourceData, meta interfac
 	if err := d.Set("mobile_phone", user.MobilePhone); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := d.Set("username", user.Username); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("locked", user.Locked); err != nil {
+		return diag.FromErr(err)
+	}
+	// Imported users are deleted on destroy, unless configured otherwise.
+	if _, ok := d.GetOk("delete_behavior"); !ok {
+		if err := d.Set("delete_behavior", "delete"); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := d.Set("mfa_enabled", user.MfaEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("require_mfa", user.MfaEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("version", user.Version); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("linked_users", flattenLinkedUsers(user.LinkedUsers)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("created", map[string]interface{}{"at": fmt.Sprint(user.Created.At), "by": user.Created.By}); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("modified", map[string]interface{}{"at": fmt.Sprint(user.Modified.At), "by": user.Modified.By}); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("ignore_roles").(bool) {
+		if err := d.Set("role_ids", nil); err != nil {
+			return diag.FromErr(err)
+		}
+		return diags
+	}
+
+	roleIds, err := api.GetAssignedRoleIDs(user.ID)
+	if isNotFound(err) {
+		return removeFromState(d, "User")
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	if err := d.Set("role_ids", roleIds.RoleIds); err != nil {
 		return diag.FromErr(err)
 	}
@@ -123,20 +331,67 @@ func resourceUserRead(ctx context.Context, d *schema.ResourceData, meta interfac
 }
 
 func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	api := meta.(*alertlogic.API)
+	api := meta.(*client).api
+
+	var diags diag.Diagnostics
+
+	// These only affect how the provider manages the user, there is nothing to send.
+	if !d.HasChangesExcept("delete_behavior", "send_welcome_email") {
+		return diags
+	}
 
 	userId := d.Id()
 
-	userRequest := alertlogic.UpdateUserRequest{
-		Name:        d.Get("name").(string),
-		Email:       d.Get("email").(string),
-		Active:      d.Get("active").(bool),
-		MobilePhone: d.Get("mobile_phone").(string),
+	// Alert Logic emails a link to set the password when a user is updated through
+	// UpdateUserDetails without one, which is how a changed welcome_email_trigger resends the
+	// welcome email. Otherwise it is only used to send a changed password.
+	fullUpdate := d.HasChange("welcome_email_trigger") ||
+		(d.HasChange("password") && d.Get("password").(string) != "")
+	if fullUpdate {
+		userRequest := alertlogic.UpdateUserRequest{
+			Name:        d.Get("name").(string),
+			Email:       d.Get("email").(string),
+			Active:      d.Get("active").(bool),
+			MobilePhone: d.Get("mobile_phone").(string),
+		}
+
+		oneTimePassword := false
+		if !d.HasChange("welcome_email_trigger") {
+			userRequest.Password = d.Get("password").(string)
+			oneTimePassword = d.Get("one_time_password").(bool)
+		}
+
+		_, err := api.UpdateUserDetails(userId, userRequest, oneTimePassword)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
-	_, err := api.UpdateUserDetails(userId, userRequest, false)
-	if err != nil {
-		return diag.FromErr(err)
+	// All other changes are sent as a partial update, which never emails the user.
+	fields := map[string]interface{}{}
+	for _, key := range []string{"name", "email", "mobile_phone"} {
+		if d.HasChange(key) {
+			fields[key] = d.Get(key).(string)
+		}
+	}
+	// The client library omits active when it is false, so a full update cannot deactivate.
+	if d.HasChange("active") || (fullUpdate && !d.Get("active").(bool)) {
+		fields["active"] = d.Get("active").(bool)
+	}
+	if d.HasChange("require_mfa") {
+		fields["mfa_enabled"] = d.Get("require_mfa").(bool)
+	}
+	if d.HasChange("locked") && !d.Get("locked").(bool) {
+		fields["locked"] = false
+	}
+	if len(fields) > 0 {
+		if err := updateUserFields(ctx, meta.(*client), userId, fields); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.Get("ignore_roles").(bool) {
+		return resourceUserRead(ctx, d, meta)
 	}
 
 	// Take care of the user's roles.
@@ -171,13 +426,78 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 	return resourceUserRead(ctx, d, meta)
 }
 
+// updateUserFields updates fields of a user that the client library cannot update, either
+// because it does not support them or because it omits false values, e.g. `active`. Unlike
+// UpdateUserDetails without a password, the partial update does not email the user.
+func updateUserFields(ctx context.Context, c *client, userId string, fields map[string]interface{}) error {
+	err := c.aimsRequest(ctx, http.MethodPost,
+		fmt.Sprintf("aims/v1/%s/users/%s", c.api.AccountID, userId), fields, nil)
+	if err != nil {
+		return fmt.Errorf("error updating user %s: %s", userId, err)
+	}
+
+	return nil
+}
+
+// resourceUserCustomizeDiff validates that roles are only set when they are managed, that
+// the password settings are consistent and that users are never locked.
+func resourceUserCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Get("ignore_roles").(bool) && len(d.Get("role_ids").([]interface{})) > 0 {
+		return fmt.Errorf("role_ids cannot be set when ignore_roles is true")
+	}
+
+	if d.HasChange("locked") && d.Get("locked").(bool) {
+		return fmt.Errorf("users cannot be locked, set active to false to keep a user from logging in")
+	}
+
+	// Resending the welcome email requires an update without a password, so a new password
+	// would never reach Alert Logic.
+	passwordKnown := d.NewValueKnown("password")
+	password := d.Get("password").(string)
+	if d.Id() != "" && d.HasChange("welcome_email_trigger") && d.HasChange("password") && (!passwordKnown || password != "") {
+		return fmt.Errorf("password and welcome_email_trigger cannot be changed at the same time, Alert Logic only sends the welcome email to users updated without a password")
+	}
+
+	// A password that is only known after apply, e.g. a generated one, is checked on creation.
+	if !passwordKnown {
+		return nil
+	}
+
+	// send_welcome_email is only used on creation, a password can be set later on regardless.
+	return validateUserPassword(d.Id() == "" && d.Get("send_welcome_email").(bool), d.Get("one_time_password").(bool), password)
+}
+
+// validateUserPassword checks that a password is only set if the welcome email is not sent,
+// and that one_time_password is only set together with a password.
+func validateUserPassword(sendWelcomeEmail bool, oneTimePassword bool, password string) error {
+	if sendWelcomeEmail && password != "" {
+		return fmt.Errorf("password cannot be set when send_welcome_email is true, Alert Logic only sends the welcome email to users without a password")
+	}
+	if oneTimePassword && password == "" {
+		return fmt.Errorf("one_time_password requires password to be set")
+	}
+
+	return nil
+}
+
 func resourceUserDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	api := meta.(*alertlogic.API)
+	api := meta.(*client).api
 
 	var diags diag.Diagnostics
 
 	userId := d.Id()
 
+	if d.Get("delete_behavior").(string) == "deactivate" {
+		err := updateUserFields(ctx, meta.(*client), userId, map[string]interface{}{"active": false})
+		if err != nil && !isNotFound(err) {
+			return diag.FromErr(err)
+		}
+
+		d.SetId("")
+
+		return diags
+	}
+
 	_, err := api.DeleteUser(userId)
 	if err != nil {
 		return diag.FromErr(err)
@@ -187,3 +507,63 @@ func resourceUserDelete(ctx context.Context, d *schema.ResourceData, meta interf
 
 	return diags
 }
+
+// resourceUserImport imports a user by ID, or looks up the ID of a user imported as
+// `email:<email>` or `username:<username>`.
+func resourceUserImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 || (parts[0] != "email" && parts[0] != "username") {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	user, err := lookupUser(meta.(*client).api, parts[0], parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(user.ID)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// lookupUser looks up the single user of the account whose email or username, as given by
+// attribute, matches value. The endpoints it uses are global rather than listing all users
+// of the account, so their results are limited to the account.
+func lookupUser(api *alertlogic.API, attribute string, value string) (alertlogic.User, error) {
+	var users []alertlogic.User
+	if attribute == "email" {
+		userList, err := api.ListUsersByEmail(value, false, false, false)
+		if err != nil && !isNotFound(err) {
+			return alertlogic.User{}, err
+		}
+		users = userList.Users
+	} else {
+		user, err := api.GetUserDetailsByUsername(value, false, false, false)
+		if err != nil && !isNotFound(err) {
+			return alertlogic.User{}, err
+		}
+		if err == nil {
+			users = []alertlogic.User{user}
+		}
+	}
+
+	var matches []alertlogic.User
+	for _, u := range users {
+		if u.AccountID == api.AccountID {
+			matches = append(matches, u)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return alertlogic.User{}, fmt.Errorf("no user found with %s %q in account %s", attribute, value, api.AccountID)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, u := range matches {
+			ids = append(ids, u.ID)
+		}
+		return alertlogic.User{}, fmt.Errorf("found %d users with %s %q in account %s (%s), use the ID of one of them instead", len(matches), attribute, value, api.AccountID, strings.Join(ids, ", "))
+	}
+}
@@ -0,0 +1,313 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/duffn/go-alertlogic/alertlogic"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// accessKeyWithSecret is an access key as returned on creation, the only time the secret
+// key is available.
+type accessKeyWithSecret struct {
+	alertlogic.AccessKey
+	SecretKey string `json:"secret_key"`
+}
+
+func resourceUserAccessKey() *schema.Resource {
+	return &schema.Resource{
+		Description: `An access key of an Alert Logic user, e.g. for automation that uses the API.
+
+The secret key is only available when the key is created. It is stored in the Terraform state, unless ` + "`pgp_key`" + ` is set to store it encrypted instead.
+
+Set ` + "`rotation_days`" + ` to replace the key once it reaches that age. Combine it with ` + "`create_before_destroy`" + ` so the new key exists before the old one is deleted, and run ` + "`terraform apply`" + ` on a schedule.
+
+[API reference](https://console.cloudinsight.alertlogic.com/api/aims/#api-AIMS_User_Access_Keys-CreateAccessKey)`,
+		CreateContext: resourceUserAccessKeyCreate,
+		ReadContext:   resourceUserAccessKeyRead,
+		UpdateContext: resourceUserAccessKeyUpdate,
+		DeleteContext: readOnlyDelete(resourceUserAccessKeyDelete),
+		CustomizeDiff: customdiff.All(
+			customizeDiffReadOnly,
+			resourceUserAccessKeyCustomizeDiff,
+		),
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				userId, accessKeyId, err := parseUserAccessKeyId(d.Id())
+				if err != nil {
+					return nil, err
+				}
+
+				d.Set("user_id", userId)
+				d.Set("access_key_id", accessKeyId)
+
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Description: "The ID of the user the access key belongs to.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"label": {
+				Description: "A label describing what the access key is used for.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"pgp_key": {
+				Description: "A PGP public key, either ASCII armored or base64 encoded, to encrypt the secret key with. If set, `secret_key` is left empty and `encrypted_secret` is set instead.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"rotation_days": {
+				Description:  "The number of days after which the access key is replaced. Zero disables rotation.",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"rotation_required": {
+				Description: "Whether the access key is older than `rotation_days` and will be replaced.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"access_key_id": {
+				Description: "The ID of the access key.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"secret_key": {
+				Description: "The secret key. Only available if the key was created by Terraform without `pgp_key`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"encrypted_secret": {
+				Description: "The secret key, encrypted with `pgp_key` and base64 encoded.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"key_fingerprint": {
+				Description: "The fingerprint of `pgp_key`.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"last_login": {
+				Description: "When the access key was last used to authenticate, as a Unix timestamp.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"created": {
+				Description: "Information on when the record was created.",
+				Type:        schema.TypeMap,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceUserAccessKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*client)
+
+	userId := d.Get("user_id").(string)
+
+	var accessKey accessKeyWithSecret
+	err := c.aimsRequest(ctx, http.MethodPost,
+		fmt.Sprintf("aims/v1/%s/users/%s/access_keys", c.api.AccountID, userId),
+		map[string]string{"label": d.Get("label").(string)}, &accessKey)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(getUserAccessKeyId(userId, accessKey.AccessKeyID))
+
+	if pgpKey := d.Get("pgp_key").(string); pgpKey != "" {
+		fingerprint, encrypted, err := encryptWithPGPKey(pgpKey, accessKey.SecretKey)
+		if err != nil {
+			return resourceUserAccessKeyRollback(ctx, d, c, err)
+		}
+		if err := d.Set("key_fingerprint", fingerprint); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("encrypted_secret", encrypted); err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		if err := d.Set("secret_key", accessKey.SecretKey); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceUserAccessKeyRead(ctx, d, meta)
+}
+
+// resourceUserAccessKeyRollback deletes an access key whose secret could not be stored, as
+// it would be unusable.
+func resourceUserAccessKeyRollback(ctx context.Context, d *schema.ResourceData, c *client, createErr error) diag.Diagnostics {
+	diags := diag.FromErr(createErr)
+
+	if deleteDiags := resourceUserAccessKeyDelete(ctx, d, c); deleteDiags.HasError() {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Unable to delete the access key",
+			Detail:   fmt.Sprintf("The access key %s has been created, but its secret could not be stored. Delete it manually.", d.Id()),
+		})
+		return diags
+	}
+
+	return diags
+}
+
+func resourceUserAccessKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	api := meta.(*client).api
+
+	var diags diag.Diagnostics
+
+	userId, accessKeyId, err := parseUserAccessKeyId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	user, err := api.GetUserDetails(userId, true, false, false)
+	if isNotFound(err) {
+		return removeFromState(d, "User access key")
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	accessKey, ok := findAccessKey(user, accessKeyId)
+	if !ok {
+		return removeFromState(d, "User access key")
+	}
+
+	if err := d.Set("user_id", userId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("access_key_id", accessKey.AccessKeyID); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("label", accessKey.Label); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("last_login", accessKey.LastLogin); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("created", map[string]interface{}{"at": fmt.Sprint(accessKey.Created.At), "by": accessKey.Created.By}); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("rotation_required", accessKeyRotationRequired(int64(accessKey.Created.At), d.Get("rotation_days").(int), time.Now())); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+func resourceUserAccessKeyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Only rotation_days can be updated, which is stored in the state as is.
+	return resourceUserAccessKeyRead(ctx, d, meta)
+}
+
+// resourceUserAccessKeyCustomizeDiff replaces access keys that are due for rotation, either
+// as found by the last refresh or because rotation_days has been lowered.
+func resourceUserAccessKeyCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	if d.HasChange("rotation_days") {
+		createdAt, _ := strconv.ParseInt(fmt.Sprint(d.Get("created.at")), 10, 64)
+		required := accessKeyRotationRequired(createdAt, d.Get("rotation_days").(int), time.Now())
+		if err := d.SetNew("rotation_required", false); err != nil {
+			return err
+		}
+		if required {
+			return d.ForceNew("rotation_days")
+		}
+		return nil
+	}
+
+	if !d.Get("rotation_required").(bool) {
+		return nil
+	}
+
+	if err := d.SetNew("rotation_required", false); err != nil {
+		return err
+	}
+
+	return d.ForceNew("rotation_required")
+}
+
+// accessKeyRotationRequired checks if an access key created at the given Unix timestamp is
+// older than rotationDays. A rotationDays of zero disables rotation.
+func accessKeyRotationRequired(createdAt int64, rotationDays int, now time.Time) bool {
+	if rotationDays <= 0 || createdAt <= 0 {
+		return false
+	}
+
+	return !now.Before(time.Unix(createdAt, 0).AddDate(0, 0, rotationDays))
+}
+
+func resourceUserAccessKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*client)
+
+	var diags diag.Diagnostics
+
+	userId, accessKeyId, err := parseUserAccessKeyId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.aimsRequest(ctx, http.MethodDelete,
+		fmt.Sprintf("aims/v1/%s/users/%s/access_keys/%s", c.api.AccountID, userId, accessKeyId), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// findAccessKey returns the access key of a user with the given ID. The user details must
+// have been retrieved including access keys.
+func findAccessKey(user alertlogic.User, accessKeyId string) (alertlogic.AccessKey, bool) {
+	if user.AccessKeys == nil {
+		return alertlogic.AccessKey{}, false
+	}
+
+	for _, k := range *user.AccessKeys {
+		if k.AccessKeyID == accessKeyId {
+			return k, true
+		}
+	}
+
+	return alertlogic.AccessKey{}, false
+}
+
+// getUserAccessKeyId returns the ID of a user access key.
+func getUserAccessKeyId(userId string, accessKeyId string) string {
+	return fmt.Sprintf("%s/%s", userId, accessKeyId)
+}
+
+// parseUserAccessKeyId parses the ID of a user access key. The ID should be in the format
+// `userId/accessKeyId`.
+func parseUserAccessKeyId(id string) (string, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected format of ID (%s), expected userId/accessKeyId", id)
+	}
+
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// newTestAccessKeyHandler serves a user with the access key KEY, as long as it has not been
+// deleted.
+func newTestAccessKeyHandler(t *testing.T, deleted *bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/aims/v1/12345678/users/USER/access_keys":
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["label"] != "automation" {
+				t.Errorf("unexpected body %v (%v)", body, err)
+			}
+			w.Write([]byte(`{"access_key_id":"KEY","secret_key":"SECRET","label":"automation","created":{"at":1600000000}}`))
+		case r.Method == "GET" && r.URL.Path == "/aims/v1/12345678/users/USER":
+			if r.URL.Query().Get("include_access_keys") != "true" {
+				t.Errorf("expected access keys to be included")
+			}
+			if *deleted {
+				w.Write([]byte(`{"id":"USER","access_keys":[]}`))
+				return
+			}
+			w.Write([]byte(`{"id":"USER","access_keys":[{"access_key_id":"KEY","label":"automation","last_login":1600000100,"created":{"at":1600000000}}]}`))
+		case r.Method == "DELETE" && r.URL.Path == "/aims/v1/12345678/users/USER/access_keys/KEY":
+			*deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestResourceUserAccessKey(t *testing.T) {
+	deleted := false
+	meta := newTestClient(t, newTestAccessKeyHandler(t, &deleted))
+
+	r := resourceUserAccessKey()
+	d := r.TestResourceData()
+	d.Set("user_id", "USER")
+	d.Set("label", "automation")
+
+	if diags := r.CreateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if d.Id() != "USER/KEY" {
+		t.Errorf("expected ID USER/KEY, got %s", d.Id())
+	}
+	if d.Get("secret_key").(string) != "SECRET" {
+		t.Errorf("expected the secret key to be stored")
+	}
+	if d.Get("last_login").(int) != 1600000100 {
+		t.Errorf("unexpected last login %v", d.Get("last_login"))
+	}
+
+	if diags := r.DeleteContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if !deleted {
+		t.Error("expected the access key to be deleted")
+	}
+
+	d.SetId("USER/KEY")
+	diags := r.ReadContext(context.Background(), d, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the deleted access key to be removed from the state")
+	}
+}
+
+func TestResourceUserAccessKeyCreate_invalidPGPKey(t *testing.T) {
+	deleted := false
+	meta := newTestClient(t, newTestAccessKeyHandler(t, &deleted))
+
+	r := resourceUserAccessKey()
+	d := r.TestResourceData()
+	d.Set("user_id", "USER")
+	d.Set("label", "automation")
+	d.Set("pgp_key", "not a key")
+
+	if diags := r.CreateContext(context.Background(), d, meta); !diags.HasError() {
+		t.Fatal("expected an error")
+	}
+	if !deleted {
+		t.Error("expected the unusable access key to be deleted")
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the access key not to be tracked, got %s", d.Id())
+	}
+}
+
+func TestAccessKeyRotationRequired(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	day := int64(24 * 60 * 60)
+
+	cases := []struct {
+		name         string
+		createdAt    int64
+		rotationDays int
+		expected     bool
+	}{
+		{"disabled", now.Unix() - 100*day, 0, false},
+		{"unknown creation", 0, 90, false},
+		{"young", now.Unix() - 89*day, 90, false},
+		{"due", now.Unix() - 90*day, 90, true},
+		{"overdue", now.Unix() - 100*day, 90, true},
+	}
+
+	for _, c := range cases {
+		if actual := accessKeyRotationRequired(c.createdAt, c.rotationDays, now); actual != c.expected {
+			t.Errorf("%s: expected %t, got %t", c.name, c.expected, actual)
+		}
+	}
+}
+
+func TestResourceUserAccessKeyCustomizeDiff_rotation(t *testing.T) {
+	createdAt := time.Now().AddDate(0, 0, -30).Unix()
+
+	cases := []struct {
+		name             string
+		rotationDays     string
+		rotationRequired string
+		config           int
+		expected         bool
+	}{
+		{"not due", "90", "false", 90, false},
+		{"due on refresh", "90", "true", 90, true},
+		{"rotation days lowered", "90", "false", 7, true},
+		{"rotation days raised", "7", "true", 90, false},
+	}
+
+	for _, c := range cases {
+		state := &terraform.InstanceState{
+			ID: "USER/KEY",
+			Attributes: map[string]string{
+				"id":                "USER/KEY",
+				"user_id":           "USER",
+				"access_key_id":     "KEY",
+				"rotation_days":     c.rotationDays,
+				"rotation_required": c.rotationRequired,
+				"created.%":         "1",
+				"created.at":        fmt.Sprint(createdAt),
+			},
+		}
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"user_id":       "USER",
+			"rotation_days": c.config,
+		})
+
+		diff, err := resourceUserAccessKey().Diff(context.Background(), state, config, &client{})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", c.name, err)
+		}
+		if actual := diff != nil && diff.RequiresNew(); actual != c.expected {
+			t.Errorf("%s: expected replacement %t, got %t", c.name, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceUserMfaReset() *schema.Resource {
+	return &schema.Resource{
+		Description: `Clears the MFA device a user has enrolled, so they enroll a new one on their next login.
+
+The reset happens when the resource is created, i.e. initially and whenever ` + "`triggers`" + ` change. Reading or destroying the resource does nothing.
+
+[API reference](https://console.cloudinsight.alertlogic.com/api/aims/#api-AIMS_MFA_Resources-DeleteMFA)`,
+		CreateContext: resourceUserMfaResetCreate,
+		ReadContext:   resourceUserMfaResetRead,
+		DeleteContext: readOnlyDelete(resourceUserMfaResetDelete),
+		CustomizeDiff: customizeDiffReadOnly,
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Description: "The ID of the user whose MFA device is cleared.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"triggers": {
+				Description: "Arbitrary values that, when changed, clear the MFA device again, e.g. a ticket number.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"email": {
+				Description: "The email address of the user.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceUserMfaResetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*client)
+
+	var diags diag.Diagnostics
+
+	userId := d.Get("user_id").(string)
+
+	user, err := c.api.GetUserDetails(userId, false, false, false)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.aimsRequest(ctx, http.MethodDelete, fmt.Sprintf("aims/v1/user/mfa/%s", url.PathEscape(user.Email)), nil, nil)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("error clearing the MFA device of user %s: %s", userId, err))
+	}
+
+	if err := d.Set("email", user.Email); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%d", userId, time.Now().UnixNano()))
+
+	return diags
+}
+
+func resourceUserMfaResetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// There is nothing to read, a reset has no lasting state in Alert Logic.
+
+	return diags
+}
+
+func resourceUserMfaResetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	d.SetId("")
+
+	return diags
+}
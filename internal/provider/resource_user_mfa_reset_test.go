@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestResourceUserMfaResetCreate(t *testing.T) {
+	reset := false
+	meta := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/aims/v1/12345678/users/USER":
+			w.Write([]byte(`{"id":"USER","email":"bob+test@bobloblawlaw.com"}`))
+		case r.Method == "DELETE" && r.URL.Path == "/aims/v1/user/mfa/bob+test@bobloblawlaw.com":
+			reset = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	r := resourceUserMfaReset()
+	d := r.TestResourceData()
+	d.Set("user_id", "USER")
+
+	if diags := r.CreateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if !reset {
+		t.Error("expected the MFA device to be cleared")
+	}
+	if d.Id() == "" {
+		t.Error("expected an ID")
+	}
+	if d.Get("email").(string) != "bob+test@bobloblawlaw.com" {
+		t.Errorf("unexpected email %s", d.Get("email"))
+	}
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceUserRoleAssignment() *schema.Resource {
+	return &schema.Resource{
+		Description: `A single role granted to an Alert Logic user.
+
+Unlike ` + "`role_ids`" + ` of ` + "`alertlogic_user`" + `, this resource is not authoritative: it only manages this one role and leaves other roles of the user alone. Set ` + "`ignore_roles`" + ` on ` + "`alertlogic_user`" + ` when using both for the same user.
+
+[API reference](https://console.cloudinsight.alertlogic.com/api/aims/#api-AIMS_User_Roles_Resources-GrantUserRole)`,
+		CreateContext: resourceUserRoleAssignmentCreate,
+		ReadContext:   resourceUserRoleAssignmentRead,
+		DeleteContext: readOnlyDelete(resourceUserRoleAssignmentDelete),
+		CustomizeDiff: customizeDiffReadOnly,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				userId, roleId, err := parseUserRoleAssignmentId(d.Id())
+				if err != nil {
+					return nil, err
+				}
+
+				d.Set("user_id", userId)
+				d.Set("role_id", roleId)
+
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Description: "The ID of the user.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"role_id": {
+				Description: "The ID of the role to grant to the user.",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+		},
+	}
+}
+
+func resourceUserRoleAssignmentCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	api := meta.(*client).api
+
+	userId := d.Get("user_id").(string)
+	roleId := d.Get("role_id").(string)
+
+	_, err := api.GrantUserRole(userId, roleId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(getUserRoleAssignmentId(userId, roleId))
+	return resourceUserRoleAssignmentRead(ctx, d, meta)
+}
+
+func resourceUserRoleAssignmentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	api := meta.(*client).api
+
+	var diags diag.Diagnostics
+
+	userId, roleId, err := parseUserRoleAssignmentId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	roleIds, err := api.GetAssignedRoleIDs(userId)
+	if isNotFound(err) {
+		return removeFromState(d, "User role assignment")
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !contains(roleIds.RoleIds, roleId) {
+		return removeFromState(d, "User role assignment")
+	}
+
+	if err := d.Set("user_id", userId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("role_id", roleId); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+func resourceUserRoleAssignmentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	api := meta.(*client).api
+
+	var diags diag.Diagnostics
+
+	_, err := api.RevokeUserRole(d.Get("user_id").(string), d.Get("role_id").(string))
+	if err != nil && !isNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// getUserRoleAssignmentId returns the ID of a user role assignment.
+func getUserRoleAssignmentId(userId string, roleId string) string {
+	return fmt.Sprintf("%s/%s", userId, roleId)
+}
+
+// parseUserRoleAssignmentId parses the ID of a user role assignment. The ID should be in the
+// format `userId/roleId`.
+func parseUserRoleAssignmentId(id string) (string, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected format of ID (%s), expected userId/roleId", id)
+	}
+
+	return parts[0], parts[1], nil
+}
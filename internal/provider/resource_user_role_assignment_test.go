@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestParseUserRoleAssignmentId(t *testing.T) {
+	userId, roleId, err := parseUserRoleAssignmentId("USER/ROLE")
+	if err != nil || userId != "USER" || roleId != "ROLE" {
+		t.Errorf("unexpected result %s, %s, %v", userId, roleId, err)
+	}
+
+	for _, id := range []string{"USER", "USER/", "/ROLE", ""} {
+		if _, _, err := parseUserRoleAssignmentId(id); err == nil {
+			t.Errorf("expected an error for %q", id)
+		}
+	}
+}
+
+func TestResourceUserRoleAssignmentRead(t *testing.T) {
+	meta := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/aims/v1/12345678/users/USER/role_ids":
+			w.Write([]byte(`{"role_ids":["ROLE"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	cases := []struct {
+		id      string
+		removed bool
+	}{
+		{"USER/ROLE", false},
+		{"USER/REVOKED", true},
+		{"DELETED/ROLE", true},
+	}
+
+	for _, c := range cases {
+		r := resourceUserRoleAssignment()
+		d := r.TestResourceData()
+		d.SetId(c.id)
+
+		diags := r.ReadContext(context.Background(), d, meta)
+		if diags.HasError() {
+			t.Fatalf("%s: unexpected error: %v", c.id, diags)
+		}
+		if removed := d.Id() == ""; removed != c.removed {
+			t.Errorf("%s: expected removed %t, got %t", c.id, c.removed, removed)
+		}
+	}
+}
+
+func TestResourceUserRoleAssignmentDelete_notFound(t *testing.T) {
+	meta := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	r := resourceUserRoleAssignment()
+	d := r.TestResourceData()
+	d.SetId("USER/ROLE")
+	d.Set("user_id", "USER")
+	d.Set("role_id", "ROLE")
+
+	if diags := r.DeleteContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the assignment to be removed from the state")
+	}
+}
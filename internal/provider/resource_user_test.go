@@ -0,0 +1,649 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// newTestUserCreateHandler serves user creation, failing to grant the role `failing` and,
+// unless deleteSucceeds, failing to delete the user.
+func newTestUserCreateHandler(deleteSucceeds bool, deleted *bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/aims/v1/12345678/users":
+			w.Write([]byte(`{"id":"USER","name":"Bob Loblaw","email":"bob@bobloblawlaw.com"}`))
+		case r.Method == "PUT" && r.URL.Path == "/aims/v1/12345678/users/USER/roles/failing":
+			w.WriteHeader(http.StatusForbidden)
+		case r.Method == "PUT":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "DELETE" && r.URL.Path == "/aims/v1/12345678/users/USER":
+			if !deleteSucceeds {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			*deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+var testUserConfig = map[string]interface{}{
+	"name":     "Bob Loblaw",
+	"email":    "bob@bobloblawlaw.com",
+	"active":   true,
+	"role_ids": []interface{}{"granted", "failing", "skipped"},
+}
+
+func TestResourceUserCreate_rollsBackOnFailedGrant(t *testing.T) {
+	deleted := false
+	meta := newTestClient(t, newTestUserCreateHandler(true, &deleted))
+
+	r := resourceUser()
+	d := r.TestResourceData()
+	for k, v := range testUserConfig {
+		d.Set(k, v)
+	}
+
+	diags := r.CreateContext(context.Background(), d, meta)
+	if !diags.HasError() {
+		t.Fatal("expected an error")
+	}
+	if !deleted {
+		t.Error("expected the user to be deleted")
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the user not to be tracked, got %s", d.Id())
+	}
+}
+
+func TestResourceUserCreate_keepsPartialStateWhenRollbackFails(t *testing.T) {
+	deleted := false
+	meta := newTestClient(t, newTestUserCreateHandler(false, &deleted))
+
+	r := resourceUser()
+	d := r.TestResourceData()
+	for k, v := range testUserConfig {
+		d.Set(k, v)
+	}
+
+	diags := r.CreateContext(context.Background(), d, meta)
+	if !diags.HasError() {
+		t.Fatal("expected an error")
+	}
+	if d.Id() != "USER" {
+		t.Errorf("expected the user to be tracked, got %q", d.Id())
+	}
+
+	roleIds := expandInterfaceToStringList(d.Get("role_ids"))
+	if len(roleIds) != 1 || roleIds[0] != "granted" {
+		t.Errorf("expected only the granted role in the state, got %v", roleIds)
+	}
+}
+
+// unknownValue is how the SDK represents a value that is only known after apply.
+const unknownValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+func TestResourceUserCustomizeDiff_password(t *testing.T) {
+	cases := []struct {
+		name        string
+		config      map[string]interface{}
+		expectError bool
+	}{
+		{"welcome email", map[string]interface{}{}, false},
+		{"password", map[string]interface{}{"send_welcome_email": false, "password": "Secret123!"}, false},
+		{"one-time password", map[string]interface{}{"send_welcome_email": false, "password": "Secret123!", "one_time_password": true}, false},
+		{"password with welcome email", map[string]interface{}{"password": "Secret123!"}, true},
+		{"one-time password without password", map[string]interface{}{"send_welcome_email": false, "one_time_password": true}, true},
+		{"unknown one-time password", map[string]interface{}{"send_welcome_email": false, "password": unknownValue, "one_time_password": true}, false},
+		{"unknown password with welcome email", map[string]interface{}{"password": unknownValue}, false},
+	}
+
+	for _, tc := range cases {
+		raw := map[string]interface{}{
+			"name":  "Bob Loblaw",
+			"email": "bob@bobloblawlaw.com",
+		}
+		for k, v := range tc.config {
+			raw[k] = v
+		}
+
+		_, err := resourceUser().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), &client{})
+		if (err != nil) != tc.expectError {
+			t.Errorf("%s: expected error %t, got %v", tc.name, tc.expectError, err)
+		}
+	}
+}
+
+func TestResourceUserImport(t *testing.T) {
+	meta := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method != "GET" || r.URL.Path == "/aims/v1/12345678/users":
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/aims/v1/users/email/bob@bobloblawlaw.com":
+			w.Write([]byte(`{"users":[{"id":"BOB","account_id":"12345678","name":"Bob Loblaw","email":"Bob@BobLoblawLaw.com","username":"bob"}]}`))
+		case r.URL.Path == "/aims/v1/users/email/gob@bluth.com":
+			w.Write([]byte(`{"users":[
+				{"id":"GOB1","account_id":"12345678","name":"Gob Bluth","email":"gob@bluth.com","username":"gob"},
+				{"id":"GOB2","account_id":"12345678","name":"Gob Bluth","email":"gob@bluth.com","username":"gob2"}
+			]}`))
+		case r.URL.Path == "/aims/v1/user/username/gob2":
+			w.Write([]byte(`{"id":"GOB2","account_id":"12345678","name":"Gob Bluth","email":"gob@bluth.com","username":"gob2"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	cases := []struct {
+		id       string
+		expected string
+		err      bool
+	}{
+		{id: "BOB", expected: "BOB"},
+		{id: "email:bob@bobloblawlaw.com", expected: "BOB"},
+		{id: "username:gob2", expected: "GOB2"},
+		{id: "email:gob@bluth.com", err: true},
+		{id: "username:buster", err: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.id, func(t *testing.T) {
+			r := resourceUser()
+			d := r.TestResourceData()
+			d.SetId(c.id)
+
+			result, err := r.Importer.StateContext(context.Background(), d, meta)
+			if c.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(result) != 1 || result[0].Id() != c.expected {
+				t.Errorf("expected ID %s, got %s", c.expected, result[0].Id())
+			}
+		})
+	}
+}
+
+func TestResourceUserCreate_requireMfa(t *testing.T) {
+	var mfaRequest map[string]interface{}
+	meta := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/aims/v1/12345678/users":
+			w.Write([]byte(`{"id":"USER","name":"Bob Loblaw","email":"bob@bobloblawlaw.com"}`))
+		case r.Method == "POST" && r.URL.Path == "/aims/v1/12345678/users/USER":
+			json.NewDecoder(r.Body).Decode(&mfaRequest)
+			w.Write([]byte(`{"id":"USER","mfa_enabled":true}`))
+		case r.Method == "GET" && r.URL.Path == "/aims/v1/user/USER":
+			w.Write([]byte(`{"id":"USER","name":"Bob Loblaw","email":"bob@bobloblawlaw.com","mfa_enabled":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	r := resourceUser()
+	d := r.TestResourceData()
+	d.Set("name", "Bob Loblaw")
+	d.Set("email", "bob@bobloblawlaw.com")
+	d.Set("active", true)
+	d.Set("ignore_roles", true)
+	d.Set("require_mfa", true)
+
+	if diags := r.CreateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if len(mfaRequest) != 1 || mfaRequest["mfa_enabled"] != true {
+		t.Errorf("unexpected MFA request %v", mfaRequest)
+	}
+	if !d.Get("require_mfa").(bool) {
+		t.Error("expected require_mfa to be read back")
+	}
+}
+
+func TestResourceUserDelete_deleteBehavior(t *testing.T) {
+	for _, behavior := range []string{"delete", "deactivate"} {
+		t.Run(behavior, func(t *testing.T) {
+			var deleted bool
+			var update map[string]interface{}
+			meta := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == "DELETE" && r.URL.Path == "/aims/v1/12345678/users/USER":
+					deleted = true
+					w.WriteHeader(http.StatusNoContent)
+				case r.Method == "POST" && r.URL.Path == "/aims/v1/12345678/users/USER":
+					json.NewDecoder(r.Body).Decode(&update)
+					w.Write([]byte(`{"id":"USER","active":false}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+
+			r := resourceUser()
+			d := r.TestResourceData()
+			d.SetId("USER")
+			d.Set("delete_behavior", behavior)
+
+			if diags := r.DeleteContext(context.Background(), d, meta); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if d.Id() != "" {
+				t.Errorf("expected the user to be removed from the state")
+			}
+
+			if behavior == "delete" && (!deleted || update != nil) {
+				t.Errorf("expected the user to be deleted, got deleted %t and update %v", deleted, update)
+			}
+			if behavior == "deactivate" && (deleted || len(update) != 1 || update["active"] != false) {
+				t.Errorf("expected the user to be deactivated, got deleted %t and update %v", deleted, update)
+			}
+		})
+	}
+}
+
+func TestResourceUserCustomizeDiff_locked(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "USER",
+		Attributes: map[string]string{
+			"id":                 "USER",
+			"name":               "Bob Loblaw",
+			"email":              "bob@bobloblawlaw.com",
+			"active":             "true",
+			"send_welcome_email": "true",
+			"delete_behavior":    "delete",
+			"locked":             "true",
+		},
+	}
+
+	cases := []struct {
+		name        string
+		locked      interface{}
+		stateLocked string
+		expectError bool
+	}{
+		{"unlock", false, "true", false},
+		{"keep locked", true, "true", false},
+		{"lock", true, "false", true},
+		{"not managed", nil, "false", false},
+	}
+
+	for _, tc := range cases {
+		raw := map[string]interface{}{
+			"name":  "Bob Loblaw",
+			"email": "bob@bobloblawlaw.com",
+		}
+		if tc.locked != nil {
+			raw["locked"] = tc.locked
+		}
+		state.Attributes["locked"] = tc.stateLocked
+
+		_, err := resourceUser().Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), &client{})
+		if (err != nil) != tc.expectError {
+			t.Errorf("%s: expected error %t, got %v", tc.name, tc.expectError, err)
+		}
+	}
+}
+
+// newTestIgnoredRolesHandler serves a single user and fails the test if its roles are looked
+// up or changed.
+func newTestIgnoredRolesHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/aims/v1/user/USER":
+			w.Write([]byte(`{"id":"USER","name":"Bob Loblaw","email":"bob@bobloblawlaw.com","active":true}`))
+		case r.Method == "POST" && r.URL.Path == "/aims/v1/12345678/users/USER":
+			w.Write([]byte(`{"id":"USER","name":"Bob Loblaw","email":"bob@bobloblawlaw.com","active":true}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestResourceUserRead_ignoreRoles(t *testing.T) {
+	meta := newTestClient(t, newTestIgnoredRolesHandler(t))
+
+	r := resourceUser()
+	d := r.TestResourceData()
+	d.SetId("USER")
+	d.Set("ignore_roles", true)
+	d.Set("role_ids", []interface{}{"ROLE"})
+
+	if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if roleIds := d.Get("role_ids").([]interface{}); len(roleIds) != 0 {
+		t.Errorf("expected role_ids to be cleared, got %v", roleIds)
+	}
+}
+
+func TestResourceUserUpdate_ignoreRoles(t *testing.T) {
+	meta := newTestClient(t, newTestIgnoredRolesHandler(t))
+
+	r := resourceUser()
+	d := r.TestResourceData()
+	d.SetId("USER")
+	d.Set("name", "Bob Loblaw")
+	d.Set("email", "bob@bobloblawlaw.com")
+	d.Set("active", true)
+	d.Set("ignore_roles", true)
+
+	if diags := r.UpdateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+}
+
+func TestResourceUserUpdate_stopIgnoringRoles(t *testing.T) {
+	roleIds := `["ROLE"]`
+	var revoked []string
+	meta := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/aims/v1/12345678/users/USER/role_ids":
+			w.Write([]byte(`{"role_ids":` + roleIds + `}`))
+		case r.Method == "DELETE" && r.URL.Path == "/aims/v1/12345678/users/USER/roles/ROLE":
+			revoked = append(revoked, "ROLE")
+			roleIds = `[]`
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "GET" && r.URL.Path == "/aims/v1/user/USER":
+			w.Write([]byte(`{"id":"USER","name":"Bob Loblaw","email":"bob@bobloblawlaw.com","active":true}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	state := &terraform.InstanceState{
+		ID: "USER",
+		Attributes: map[string]string{
+			"id":                 "USER",
+			"name":               "Bob Loblaw",
+			"email":              "bob@bobloblawlaw.com",
+			"active":             "true",
+			"send_welcome_email": "true",
+			"delete_behavior":    "delete",
+			"ignore_roles":       "true",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":         "Bob Loblaw",
+		"email":        "bob@bobloblawlaw.com",
+		"ignore_roles": false,
+	})
+
+	r := resourceUser()
+	diff, err := r.Diff(context.Background(), state, config, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	newState, diags := r.Apply(context.Background(), state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if len(revoked) != 1 {
+		t.Errorf("expected the role to be revoked in the same apply, got %v", revoked)
+	}
+	if newState.Attributes["role_ids.#"] != "0" {
+		t.Errorf("expected role_ids to be read back, got %v", newState.Attributes)
+	}
+}
+
+// testUserUpdate plans and applies changes to the configuration of the user USER, returning
+// the bodies of all update requests.
+func testUserUpdate(t *testing.T, changes map[string]interface{}) []map[string]interface{} {
+	updates, _ := testUserUpdateRequests(t, changes)
+	return updates
+}
+
+// testUserUpdateRequests is testUserUpdate, also returning the number of requests made.
+func testUserUpdateRequests(t *testing.T, changes map[string]interface{}) ([]map[string]interface{}, int) {
+	var updates []map[string]interface{}
+	requests := 0
+	meta := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/aims/v1/12345678/users/USER":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			updates = append(updates, body)
+			w.Write([]byte(`{"id":"USER"}`))
+		case r.Method == "GET" && r.URL.Path == "/aims/v1/user/USER":
+			w.Write([]byte(`{"id":"USER","name":"Bob Loblaw","email":"bob@bobloblawlaw.com","active":true}`))
+		case r.Method == "GET" && r.URL.Path == "/aims/v1/12345678/users/USER/role_ids":
+			w.Write([]byte(`{"role_ids":[]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	state := &terraform.InstanceState{
+		ID: "USER",
+		Attributes: map[string]string{
+			"id":                    "USER",
+			"name":                  "Bob Loblaw",
+			"email":                 "bob@bobloblawlaw.com",
+			"active":                "true",
+			"send_welcome_email":    "false",
+			"password":              "Secret123!",
+			"welcome_email_trigger": "1",
+			"delete_behavior":       "delete",
+			"ignore_roles":          "false",
+			"one_time_password":     "false",
+		},
+	}
+	config := map[string]interface{}{
+		"name":                  "Bob Loblaw",
+		"email":                 "bob@bobloblawlaw.com",
+		"send_welcome_email":    false,
+		"password":              "Secret123!",
+		"welcome_email_trigger": "1",
+	}
+	for k, v := range changes {
+		config[k] = v
+	}
+
+	r := resourceUser()
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, diags := r.Apply(context.Background(), state, diff, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	return updates, requests
+}
+
+func TestResourceUserUpdate_providerSettings(t *testing.T) {
+	_, requests := testUserUpdateRequests(t, map[string]interface{}{
+		"delete_behavior":    "deactivate",
+		"send_welcome_email": true,
+	})
+
+	if requests != 0 {
+		t.Errorf("expected no requests, got %d", requests)
+	}
+}
+
+func TestResourceUserUpdate_partial(t *testing.T) {
+	updates := testUserUpdate(t, map[string]interface{}{"name": "Robert Loblaw"})
+
+	if len(updates) != 1 || len(updates[0]) != 1 || updates[0]["name"] != "Robert Loblaw" {
+		t.Errorf("expected a single partial update of the name, got %v", updates)
+	}
+	for _, u := range updates {
+		if _, ok := u["email"]; ok && u["password"] == nil {
+			t.Errorf("expected no password-less full update, got %v", u)
+		}
+	}
+}
+
+func TestResourceUserUpdate_welcomeEmail(t *testing.T) {
+	updates := testUserUpdate(t, map[string]interface{}{"welcome_email_trigger": "2"})
+
+	if len(updates) != 1 || updates[0]["email"] != "bob@bobloblawlaw.com" || updates[0]["password"] != nil {
+		t.Errorf("expected a single password-less full update, got %v", updates)
+	}
+}
+
+func TestResourceUserUpdate_password(t *testing.T) {
+	updates := testUserUpdate(t, map[string]interface{}{"password": "Secret456!"})
+
+	if len(updates) != 1 || updates[0]["password"] != "Secret456!" {
+		t.Errorf("expected a single full update with the password, got %v", updates)
+	}
+}
+
+func TestResourceUserCustomizeDiff_passwordOfExistingUser(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "USER",
+		Attributes: map[string]string{
+			"id":                 "USER",
+			"name":               "Bob Loblaw",
+			"email":              "bob@bobloblawlaw.com",
+			"active":             "true",
+			"send_welcome_email": "true",
+			"delete_behavior":    "delete",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":     "Bob Loblaw",
+		"email":    "bob@bobloblawlaw.com",
+		"password": "Secret123!",
+	})
+
+	if _, err := resourceUser().Diff(context.Background(), state, config, &client{}); err != nil {
+		t.Errorf("expected a password to be settable on an existing user, got %s", err)
+	}
+}
+
+func TestResourceUserCustomizeDiff_passwordWithWelcomeEmailTrigger(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "USER",
+		Attributes: map[string]string{
+			"id":                    "USER",
+			"name":                  "Bob Loblaw",
+			"email":                 "bob@bobloblawlaw.com",
+			"active":                "true",
+			"send_welcome_email":    "false",
+			"password":              "Secret123!",
+			"welcome_email_trigger": "1",
+			"delete_behavior":       "delete",
+		},
+	}
+
+	cases := []struct {
+		name        string
+		password    string
+		trigger     string
+		expectError bool
+	}{
+		{"password", "Secret456!", "1", false},
+		{"trigger", "Secret123!", "2", false},
+		{"trigger and password", "Secret456!", "2", true},
+		{"trigger and unknown password", unknownValue, "2", true},
+		{"trigger and removed password", "", "2", false},
+	}
+
+	for _, tc := range cases {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":                  "Bob Loblaw",
+			"email":                 "bob@bobloblawlaw.com",
+			"send_welcome_email":    false,
+			"password":              tc.password,
+			"welcome_email_trigger": tc.trigger,
+		})
+
+		_, err := resourceUser().Diff(context.Background(), state, config, &client{})
+		if (err != nil) != tc.expectError {
+			t.Errorf("%s: expected error %t, got %v", tc.name, tc.expectError, err)
+		}
+	}
+}
+
+func TestResourceUserCreate_passwordWithWelcomeEmail(t *testing.T) {
+	// A password that was unknown when the plan was checked must still not be dropped.
+	meta := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	r := resourceUser()
+	d := r.TestResourceData()
+	d.Set("name", "Bob Loblaw")
+	d.Set("email", "bob@bobloblawlaw.com")
+	d.Set("send_welcome_email", true)
+	d.Set("password", "Secret123!")
+
+	if diags := r.CreateContext(context.Background(), d, meta); !diags.HasError() {
+		t.Error("expected an error for a password with send_welcome_email")
+	}
+	if d.Id() != "" {
+		t.Error("expected no user to be created")
+	}
+}
+
+func TestResourceUserRead_computed(t *testing.T) {
+	cases := map[string]struct {
+		mfaEnabled string
+		expected   bool
+	}{
+		"mfa enabled": {`,"mfa_enabled":true`, true},
+		"mfa not set": {``, false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			meta := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/aims/v1/user/USER":
+					w.Write([]byte(`{"id":"USER","name":"Bob Loblaw","email":"bob@bobloblawlaw.com","username":"bob",
+						"active":true,"locked":true,"version":3` + c.mfaEnabled + `,
+						"linked_users":[{"user_id":42,"location":"defender-uk-newport"}],
+						"created":{"at":1600000000,"by":"ADMIN"},"modified":{"at":1600000100,"by":"USER"}}`))
+				case "/aims/v1/12345678/users/USER/role_ids":
+					w.Write([]byte(`{"role_ids":[]}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+
+			r := resourceUser()
+			d := r.TestResourceData()
+			d.SetId("USER")
+
+			if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			expected := map[string]interface{}{
+				"username":                "bob",
+				"locked":                  true,
+				"mfa_enabled":             c.expected,
+				"version":                 3,
+				"linked_users.#":          1,
+				"linked_users.0.user_id":  42,
+				"linked_users.0.location": "defender-uk-newport",
+				"created.at":              "1600000000",
+				"created.by":              "ADMIN",
+				"modified.at":             "1600000100",
+				"modified.by":             "USER",
+			}
+			for key, value := range expected {
+				if actual := d.Get(key); actual != value {
+					t.Errorf("expected %s to be %v, got %v", key, value, actual)
+				}
+			}
+		})
+	}
+}
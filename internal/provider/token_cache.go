@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// defaultTokenCacheDir is the directory tokens are cached in if no other directory is configured.
+	defaultTokenCacheDir = "~/.alertlogic/cache"
+	// tokenExpiryMargin is how long a cached token must at least remain valid to be used.
+	tokenExpiryMargin = 5 * time.Minute
+)
+
+// cachedToken is an AIMS token as stored on disk.
+type cachedToken struct {
+	Token           string `json:"token"`
+	TokenExpiration int64  `json:"token_expiration"`
+}
+
+// tokenCache persists AIMS tokens on disk, so separate provider processes, e.g. for plan and
+// apply, do not have to authenticate again. Tokens are keyed by API endpoint, account and the
+// access key ID or username they were issued for.
+type tokenCache struct {
+	dir string
+}
+
+// newTokenCache creates a token cache in dir, falling back to the default directory.
+func newTokenCache(dir string) (*tokenCache, error) {
+	if dir == "" {
+		dir = defaultTokenCacheDir
+	}
+
+	dir, err := expandHomeDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tokenCache{dir: dir}, nil
+}
+
+// path returns the file a token is cached in.
+func (c *tokenCache) path(baseURL string, accountId string, identity string) string {
+	key := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s", baseURL, accountId, identity)))
+	return filepath.Join(c.dir, fmt.Sprintf("%x.json", key))
+}
+
+// get returns a cached token that is valid for at least tokenExpiryMargin. Files readable or
+// writable by anyone but the owner are ignored.
+func (c *tokenCache) get(baseURL string, accountId string, identity string) (cachedToken, bool) {
+	path := c.path(baseURL, accountId, identity)
+
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm()&0077 != 0 {
+		return cachedToken{}, false
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cachedToken{}, false
+	}
+
+	var token cachedToken
+	if err := json.Unmarshal(data, &token); err != nil || token.Token == "" {
+		return cachedToken{}, false
+	}
+
+	if time.Unix(token.TokenExpiration, 0).Before(time.Now().Add(tokenExpiryMargin)) {
+		return cachedToken{}, false
+	}
+
+	return token, true
+}
+
+// put stores a token in the cache with permissions of 0600.
+func (c *tokenCache) put(baseURL string, accountId string, identity string, token cachedToken) error {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so a concurrent reader never sees a partial token.
+	f, err := ioutil.TempFile(c.dir, ".token")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), c.path(baseURL, accountId, identity))
+}
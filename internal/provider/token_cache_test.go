@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func newTestTokenCache(t *testing.T) *tokenCache {
+	dir, err := ioutil.TempDir("", "alertlogic")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	cache, err := newTokenCache(dir)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return cache
+}
+
+func TestTokenCache(t *testing.T) {
+	cache := newTestTokenCache(t)
+
+	token := cachedToken{Token: "token", TokenExpiration: time.Now().Add(time.Hour).Unix()}
+	if err := cache.put(defaultAPIURL, "12345678", "key", token); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	info, err := os.Stat(cache.path(defaultAPIURL, "12345678", "key"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected permissions 0600, got %o", perm)
+	}
+
+	cached, ok := cache.get(defaultAPIURL, "12345678", "key")
+	if !ok || cached != token {
+		t.Errorf("expected %+v to be cached, got %+v", token, cached)
+	}
+
+	if _, ok := cache.get(defaultAPIURL, "12345678", "otherkey"); ok {
+		t.Error("expected no token for another access key")
+	}
+	if _, ok := cache.get(defaultAPIURL, "87654321", "key"); ok {
+		t.Error("expected no token for another account")
+	}
+}
+
+func TestTokenCache_expired(t *testing.T) {
+	cache := newTestTokenCache(t)
+
+	token := cachedToken{Token: "token", TokenExpiration: time.Now().Add(time.Minute).Unix()}
+	if err := cache.put(defaultAPIURL, "12345678", "key", token); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, ok := cache.get(defaultAPIURL, "12345678", "key"); ok {
+		t.Error("expected a token about to expire to be ignored")
+	}
+}
+
+func TestTokenCache_permissions(t *testing.T) {
+	cache := newTestTokenCache(t)
+
+	token := cachedToken{Token: "token", TokenExpiration: time.Now().Add(time.Hour).Unix()}
+	if err := cache.put(defaultAPIURL, "12345678", "key", token); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := os.Chmod(cache.path(defaultAPIURL, "12345678", "key"), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, ok := cache.get(defaultAPIURL, "12345678", "key"); ok {
+		t.Error("expected a token readable by others to be ignored")
+	}
+}
@@ -0,0 +1,284 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/duffn/go-alertlogic/alertlogic"
+)
+
+// newAPI creates an Alert Logic API client that sends all requests through httpClient.
+func newAPI(httpClient *http.Client, baseURL string, accountId string, token string) (*alertlogic.API, error) {
+	api, err := alertlogic.NewWithApiToken(accountId, token)
+	if err != nil {
+		return nil, err
+	}
+	api.BaseURL = baseURL
+
+	if err := setHTTPClient(api, httpClient); err != nil {
+		return nil, err
+	}
+
+	return api, nil
+}
+
+// setHTTPClient sets the HTTP client of an Alert Logic API client. The client library has no
+// option to set it and always uses http.DefaultClient, which must not be replaced as other
+// goroutines may be using it, so its unexported field is set instead.
+func setHTTPClient(api *alertlogic.API, httpClient *http.Client) error {
+	field := reflect.ValueOf(api).Elem().FieldByName("httpClient")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(httpClient) {
+		return fmt.Errorf("unable to set the HTTP client of the Alert Logic API client")
+	}
+
+	reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().Set(reflect.ValueOf(httpClient))
+
+	return nil
+}
+
+// authTransport authenticates every request with the current AIMS token. Tokens that are
+// about to expire are refreshed before a request is sent, and a request rejected with a 401
+// is sent once more with a new token. Refreshes are serialized, so concurrent requests
+// that fail at the same time only authenticate once.
+type authTransport struct {
+	transport     http.RoundTripper
+	authenticator *authenticator
+
+	mu    sync.Mutex
+	token cachedToken
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.currentToken(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.transport.RoundTrip(withToken(req, req.Body, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The request body has been consumed, so it can only be sent again if it can be rewound.
+	body := req.Body
+	if req.Body != nil {
+		if req.GetBody == nil {
+			return resp, nil
+		}
+		if body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+
+	newToken, err := t.refreshToken(req, token)
+	if err != nil {
+		log.Printf("[WARN] Unable to refresh the Alert Logic token: %s", err)
+		return resp, nil
+	}
+
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	log.Printf("[DEBUG] Sending %s %s again after refreshing the Alert Logic token", req.Method, req.URL.Path)
+	return t.transport.RoundTrip(withToken(req, body, newToken))
+}
+
+// currentToken returns the current token, refreshing it first if it is about to expire.
+func (t *authTransport) currentToken(req *http.Request) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token.TokenExpiration != 0 && time.Unix(t.token.TokenExpiration, 0).Before(time.Now().Add(tokenExpiryMargin)) {
+		token, err := t.authenticator.authenticate(req.Context())
+		if err != nil {
+			return "", err
+		}
+		t.token = token
+	}
+
+	return t.token.Token, nil
+}
+
+// refreshToken replaces a token rejected by the API, unless a concurrent request has already
+// replaced it.
+func (t *authTransport) refreshToken(req *http.Request, rejected string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token.Token != rejected {
+		return t.token.Token, nil
+	}
+
+	token, err := t.authenticator.authenticate(req.Context())
+	if err != nil {
+		return "", err
+	}
+	t.token = token
+
+	return t.token.Token, nil
+}
+
+// withToken returns a copy of the request with the given body and token.
+func withToken(req *http.Request, body io.ReadCloser, token string) *http.Request {
+	r := req.Clone(req.Context())
+	r.Body = body
+	r.Header.Set("X-Aims-Auth-Token", token)
+	return r
+}
+
+// retryTransport retries requests that were throttled or failed with a server error, backing
+// off exponentially between attempts. Only idempotent requests are retried.
+type retryTransport struct {
+	transport  http.RoundTripper
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isIdempotent(req.Method) {
+		return t.transport.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		resp, err := t.transport.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isRetryableStatus(resp.StatusCode) || t.maxRetries == 0 {
+			return resp, nil
+		}
+
+		// Drain and close the body so the connection can be reused.
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		if attempt >= t.maxRetries {
+			return nil, fmt.Errorf("giving up after %d attempts, last HTTP status %d", attempt+1, resp.StatusCode)
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.backoff(attempt, resp)):
+		}
+	}
+}
+
+// backoff returns how long to wait before the next attempt. A Retry-After header sent with
+// the response is honored, but never exceeds the maximum backoff.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		wait := time.Duration(seconds) * time.Second
+		if wait > t.maxBackoff {
+			return t.maxBackoff
+		}
+		return wait
+	}
+
+	wait := t.minBackoff
+	for i := 0; i < attempt && wait < t.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > t.maxBackoff {
+		return t.maxBackoff
+	}
+
+	return wait
+}
+
+// rateLimitTransport limits the rate of requests and the number of requests in flight. A
+// single transport is shared by all resources and data sources, so they share one budget.
+type rateLimitTransport struct {
+	transport http.RoundTripper
+	// interval is the minimum time between the start of two requests. Zero disables the rate limit.
+	interval time.Duration
+	// slots holds a token for every request in flight. Nil disables the limit.
+	slots chan struct{}
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// newRateLimitTransport creates a rateLimitTransport. A requestsPerSecond or maxInFlight of
+// zero disables the respective limit.
+func newRateLimitTransport(transport http.RoundTripper, requestsPerSecond float64, maxInFlight int) *rateLimitTransport {
+	t := &rateLimitTransport{transport: transport}
+
+	if requestsPerSecond > 0 {
+		t.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	if maxInFlight > 0 {
+		t.slots = make(chan struct{}, maxInFlight)
+	}
+
+	return t
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+			defer func() { <-t.slots }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if t.interval > 0 {
+		t.mu.Lock()
+		now := time.Now()
+		start := t.next
+		if start.Before(now) {
+			start = now
+		}
+		t.next = start.Add(t.interval)
+		t.mu.Unlock()
+
+		if wait := time.Until(start); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	return t.transport.RoundTrip(req)
+}
+
+// isIdempotent checks if a request with the given method can safely be sent more than once.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isRetryableStatus checks if a response status indicates a throttled request or a temporary
+// server error.
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests ||
+		(statusCode >= http.StatusInternalServerError && statusCode != http.StatusNotImplemented)
+}
@@ -0,0 +1,297 @@
+package provider
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// roundTripFunc is an http.RoundTripper implemented by a function.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newTestRetryClient(maxRetries int) *http.Client {
+	return &http.Client{
+		Transport: &retryTransport{
+			transport:  http.DefaultTransport,
+			maxRetries: maxRetries,
+			minBackoff: time.Millisecond,
+			maxBackoff: 5 * time.Millisecond,
+		},
+	}
+}
+
+func TestRetryTransport_retriesIdempotentRequests(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest("PUT", server.URL, strings.NewReader(`{"key":"value"}`))
+	resp, err := newTestRetryClient(3).Do(req)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryTransport_doesNotRetryPost(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	resp, err := newTestRetryClient(3).Post(server.URL, "application/json", nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected status %d, got %d", http.StatusTooManyRequests, resp.StatusCode)
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRetryTransport_givesUp(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := newTestRetryClient(2).Get(server.URL)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "last HTTP status 502") {
+		t.Errorf("expected the final status in the error, got %s", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryTransport_backoff(t *testing.T) {
+	transport := &retryTransport{minBackoff: time.Second, maxBackoff: 5 * time.Second}
+	resp := &http.Response{Header: http.Header{}}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for attempt, wait := range expected {
+		if backoff := transport.backoff(attempt, resp); backoff != wait {
+			t.Errorf("expected backoff %s for attempt %d, got %s", wait, attempt, backoff)
+		}
+	}
+
+	resp.Header.Set("Retry-After", "3")
+	if backoff := transport.backoff(0, resp); backoff != 3*time.Second {
+		t.Errorf("expected Retry-After to be honored, got %s", backoff)
+	}
+
+	resp.Header.Set("Retry-After", "60")
+	if backoff := transport.backoff(0, resp); backoff != 5*time.Second {
+		t.Errorf("expected Retry-After to be capped, got %s", backoff)
+	}
+}
+
+func TestRateLimitTransport_limitsRequestsInFlight(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newRateLimitTransport(http.DefaultTransport, 0, 2)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Errorf("err: %s", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+}
+
+func TestRateLimitTransport_limitsRequestRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newRateLimitTransport(http.DefaultTransport, 20, 0)}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		resp.Body.Close()
+	}
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected 3 requests at 20 requests per second to take at least 100ms, took %s", elapsed)
+	}
+}
+
+func TestAuthTransport_refreshesRejectedToken(t *testing.T) {
+	var mu sync.Mutex
+	authentications := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/aims/v1/authenticate" {
+			mu.Lock()
+			authentications++
+			mu.Unlock()
+			w.Write([]byte(`{"authentication":{"token":"new"}}`))
+			return
+		}
+
+		if r.Header.Get("X-Aims-Auth-Token") != "new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != `{"key":"value"}` {
+			t.Errorf("expected the body to be sent again, got %q", body)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &authTransport{
+			transport:     http.DefaultTransport,
+			authenticator: &authenticator{httpClient: http.DefaultClient, baseURL: server.URL, username: "key", password: "secret"},
+			token:         cachedToken{Token: "expired"},
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("PUT", server.URL+"/aims/v1/12345678/users", strings.NewReader(`{"key":"value"}`))
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Errorf("err: %s", err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if authentications != 1 {
+		t.Errorf("expected to authenticate once, authenticated %d times", authentications)
+	}
+}
+
+func TestAuthTransport_refreshesExpiringToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/aims/v1/authenticate" {
+			w.Write([]byte(`{"authentication":{"token":"new"}}`))
+			return
+		}
+
+		if token := r.Header.Get("X-Aims-Auth-Token"); token != "new" {
+			t.Errorf("expected the token to be refreshed before the request, got %s", token)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: &authTransport{
+			transport:     http.DefaultTransport,
+			authenticator: &authenticator{httpClient: http.DefaultClient, baseURL: server.URL, username: "key", password: "secret"},
+			token:         cachedToken{Token: "expiring", TokenExpiration: time.Now().Add(time.Minute).Unix()},
+		},
+	}
+
+	resp, err := client.Get(server.URL + "/aims/v1/12345678/account")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewAPI_usesHTTPClient(t *testing.T) {
+	defaultClient := http.DefaultClient
+
+	requests := 0
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"role_ids":[]}`)),
+				Header:     make(http.Header),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	api, err := newAPI(httpClient, "https://api.example.com", "12345678", "token")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if http.DefaultClient != defaultClient {
+		t.Error("expected http.DefaultClient to be left alone")
+	}
+
+	if _, err := api.GetAssignedRoleIDs("USER"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected the request to be sent through the HTTP client, got %d requests", requests)
+	}
+}
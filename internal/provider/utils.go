@@ -2,9 +2,15 @@ package provider
 
 import (
 	"crypto/md5"
+	"crypto/rand"
 	"fmt"
+	"math/big"
+	"net/http"
 	"sort"
 	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // stringChecksum takes a string and returns the checksum of the string.
@@ -38,3 +44,60 @@ func expandInterfaceToStringList(list interface{}) []string {
 	}
 	return vs
 }
+
+// isNotFound checks if an error returned by the API client is an HTTP 404. The client only
+// reports the status code as part of the error message.
+func isNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), fmt.Sprintf("HTTP status %d", http.StatusNotFound))
+}
+
+// removeFromState removes a resource that no longer exists in Alert Logic from the state, so
+// Terraform plans to create it again, and warns about it.
+func removeFromState(d *schema.ResourceData, resourceType string) diag.Diagnostics {
+	id := d.Id()
+	d.SetId("")
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("%s not found", resourceType),
+		Detail:   fmt.Sprintf("%s %s no longer exists in Alert Logic and has been removed from the state.", resourceType, id),
+	}}
+}
+
+// randomPassword generates a password with upper and lower case letters, digits and symbols.
+func randomPassword() (string, error) {
+	classes := []string{
+		"ABCDEFGHJKLMNPQRSTUVWXYZ",
+		"abcdefghijkmnopqrstuvwxyz",
+		"23456789",
+		"!#$%&*+-=?@^_",
+	}
+	all := strings.Join(classes, "")
+
+	password := make([]byte, 32)
+	for i := range password {
+		// Take one character from each class first, so every class is represented.
+		chars := all
+		if i < len(classes) {
+			chars = classes[i]
+		}
+
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+		if err != nil {
+			return "", err
+		}
+		password[i] = chars[n.Int64()]
+	}
+
+	// Shuffle, so the classes are not in a predictable order.
+	for i := len(password) - 1; i > 0; i-- {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		j := n.Int64()
+		password[i], password[j] = password[j], password[i]
+	}
+
+	return string(password), nil
+}
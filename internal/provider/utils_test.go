@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func TestIsNotFound(t *testing.T) {
+	cases := map[error]bool{
+		nil: false,
+		errors.New(`error from makeRequest: HTTP status 404: content "{}"`):             true,
+		errors.New(`error from makeRequest: HTTP status 403: insufficient permissions`): false,
+	}
+
+	for err, expected := range cases {
+		if isNotFound(err) != expected {
+			t.Errorf("expected isNotFound(%v) to be %t", err, expected)
+		}
+	}
+}
+
+func TestRemoveFromState(t *testing.T) {
+	d := resourceUser().TestResourceData()
+	d.SetId("715A4EC0-9833-4D6E-9C03-A537E3F98D23")
+
+	diags := removeFromState(d, "User")
+	if d.Id() != "" {
+		t.Errorf("expected the ID to be cleared, got %s", d.Id())
+	}
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Errorf("expected a single warning, got %v", diags)
+	}
+}
+
+func TestRandomPassword(t *testing.T) {
+	password, err := randomPassword()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if len(password) != 32 {
+		t.Errorf("expected 32 characters, got %d", len(password))
+	}
+	for _, class := range []string{"ABCDEFGHJKLMNPQRSTUVWXYZ", "abcdefghijkmnopqrstuvwxyz", "23456789", "!#$%&*+-=?@^_"} {
+		if !strings.ContainsAny(password, class) {
+			t.Errorf("expected a character of %q in %q", class, password)
+		}
+	}
+}